@@ -0,0 +1,93 @@
+package graceful
+
+import (
+	"context"
+	"time"
+)
+
+// A fluent alternative to passing startup and shutdown slices to Start(), which keeps start/stop pairs adjacent:
+//
+//	graceful.NewApp().
+//		Hook(db.Connect, db.Close).
+//		Hook(cache.Connect, cache.Close).
+//		OnRun(server.Serve).
+//		WithShutdownTimeout(30 * time.Second).
+//		Run()
+type App struct {
+	runner      *Runner
+	startupFns  []Func
+	shutdownFns []Func
+	opts        []Option
+}
+
+// Creates an App which uses the default Runner, so the package-level functions (Shutdown, Go, Context, etc) apply.
+func NewApp() *App {
+	return &App{runner: defaultRunner}
+}
+
+// Uses r instead of the default Runner.
+func (a *App) WithRunner(r *Runner) *App {
+	a.runner = r
+	return a
+}
+
+// Appends startup functions.
+func (a *App) OnStart(fns ...Func) *App {
+	a.startupFns = append(a.startupFns, fns...)
+	return a
+}
+
+// Appends shutdown functions.
+func (a *App) OnStop(fns ...Func) *App {
+	a.shutdownFns = append(a.shutdownFns, fns...)
+	return a
+}
+
+// Appends run functions. See WithRun().
+func (a *App) OnRun(fns ...Func) *App {
+	a.opts = append(a.opts, WithRun(fns...))
+	return a
+}
+
+// Appends start as a startup function and prepends stop as a shutdown function, so hooks are stopped in reverse
+// registration order, before any shutdown function registered earlier. Either may be nil.
+func (a *App) Hook(start Func, stop Func) *App {
+	if start != nil {
+		a.startupFns = append(a.startupFns, start)
+	}
+	if stop != nil {
+		a.shutdownFns = append([]Func{stop}, a.shutdownFns...)
+	}
+	return a
+}
+
+// Appends options.
+func (a *App) With(opts ...Option) *App {
+	a.opts = append(a.opts, opts...)
+	return a
+}
+
+// See WithStartupTimeout().
+func (a *App) WithStartupTimeout(d time.Duration) *App {
+	return a.With(WithStartupTimeout(d))
+}
+
+// See WithShutdownTimeout().
+func (a *App) WithShutdownTimeout(d time.Duration) *App {
+	return a.With(WithShutdownTimeout(d))
+}
+
+// Same as Start() with the registered functions and options.
+func (a *App) Start() *ExitReason {
+	return a.runner.Start(a.startupFns, a.shutdownFns, a.opts...)
+}
+
+// Same as StartContext() with the registered functions and options.
+func (a *App) StartContext(ctx context.Context) *ExitReason {
+	return a.runner.StartContext(ctx, a.startupFns, a.shutdownFns, a.opts...)
+}
+
+// Same as Run() with the registered functions and options. Exits the process.
+func (a *App) Run() {
+	a.runner.Run(a.startupFns, a.shutdownFns, a.opts...)
+}
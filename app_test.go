@@ -0,0 +1,34 @@
+package graceful
+
+import (
+	"context"
+	"slices"
+	"testing"
+)
+
+func TestAppHookOrder(t *testing.T) {
+	r := New()
+
+	var order []string
+	step := func(name string) Func {
+		return func(ctx context.Context) error {
+			order = append(order, name)
+			return nil
+		}
+	}
+
+	r.Shutdown(nil)
+	er := NewApp().WithRunner(r).
+		Hook(step("start db"), step("stop db")).
+		Hook(step("start cache"), step("stop cache")).
+		OnStop(step("stop last")).
+		Start()
+
+	if !er.Clean() {
+		t.Fatalf("expected a clean exit, got %v", er)
+	}
+	want := []string{"start db", "start cache", "stop cache", "stop db", "stop last"}
+	if !slices.Equal(order, want) {
+		t.Fatalf("expected %v, got %v", want, order)
+	}
+}
@@ -0,0 +1,92 @@
+package graceful
+
+import (
+	"context"
+	"sync"
+)
+
+// Controls a lifecycle started with StartAsync().
+type Handle struct {
+	r      *Runner
+	cancel context.CancelCauseFunc
+
+	mu      sync.Mutex
+	readyCh chan struct{}
+	ready   bool
+
+	done chan struct{}
+	er   *ExitReason
+}
+
+// Same as Start(), but returns immediately instead of blocking until the lifecycle ends. Useful when embedding the
+// lifecycle in a larger program, such as a TUI application or a test harness, which needs its goroutine back.
+func StartAsync(startupFns []Func, shutdownFns []Func, opts ...Option) *Handle {
+	return defaultRunner.StartAsync(startupFns, shutdownFns, opts...)
+}
+
+// Same as the package-level StartAsync(), but scoped to this Runner.
+func (r *Runner) StartAsync(startupFns []Func, shutdownFns []Func, opts ...Option) *Handle {
+	ctx, cancel := context.WithCancelCause(context.Background())
+	h := &Handle{
+		r:       r,
+		cancel:  cancel,
+		readyCh: make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+
+	opts = append(opts, func(config *config) error {
+		config.readyFns = append(config.readyFns, h.setReady)
+		return nil
+	})
+
+	go func() {
+		defer close(h.done)
+		defer cancel(nil)
+		h.er = r.StartContext(ctx, startupFns, shutdownFns, opts...)
+	}()
+
+	return h
+}
+
+func (h *Handle) setReady() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.ready = true
+	close(h.readyCh)
+}
+
+// Returns a channel which is closed once every startup function has completed. It is never closed if startup fails or
+// is aborted, so select on Done() as well.
+func (h *Handle) Ready() <-chan struct{} {
+	return h.readyCh
+}
+
+// Returns a channel which receives the ExitReason once the lifecycle has ended. Each call returns a new channel.
+func (h *Handle) Done() <-chan *ExitReason {
+	ch := make(chan *ExitReason, 1)
+	go func() {
+		<-h.done
+		ch <- h.er
+	}()
+	return ch
+}
+
+// Begins shutdown, passing err (which can be nil) the same as Shutdown(). If startup has not completed, it is aborted
+// instead and err is reported in ExitReason.ErrStartup. Returns immediately, see Wait().
+func (h *Handle) Stop(err error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.ready {
+		h.r.Shutdown(err)
+		return
+	}
+	h.cancel(err)
+}
+
+// Blocks until the lifecycle has ended and returns its ExitReason.
+func (h *Handle) Wait() *ExitReason {
+	<-h.done
+	return h.er
+}
@@ -0,0 +1,54 @@
+package graceful
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestStartAsync(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	h := New().StartAsync(nil, nil)
+
+	select {
+	case <-h.Ready():
+	case er := <-h.Done():
+		t.Fatalf("expected startup to complete, got %v", er)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for startup")
+	}
+
+	h.Stop(errBoom)
+	if er := h.Wait(); er.ErrRuntime != errBoom {
+		t.Fatalf("expected the Stop() error as the runtime error, got %v", er.ErrRuntime)
+	}
+	if er := <-h.Done(); er.ErrRuntime != errBoom {
+		t.Fatalf("expected Done() to receive the same ExitReason, got %v", er.ErrRuntime)
+	}
+}
+
+func TestStartAsyncStopDuringStartup(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	started := make(chan struct{})
+	slow := func(ctx context.Context) error {
+		close(started)
+		<-ctx.Done()
+		return ctx.Err()
+	}
+
+	h := New().StartAsync([]Func{slow}, nil)
+	<-started
+	h.Stop(errBoom)
+
+	if er := h.Wait(); er.ErrStartup != errBoom {
+		t.Fatalf("expected the Stop() error as the startup error, got %v", er.ErrStartup)
+	}
+	select {
+	case <-h.Ready():
+		t.Fatal("expected Ready() not to be closed after an aborted startup")
+	default:
+	}
+}
@@ -0,0 +1,152 @@
+package graceful
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"sync"
+)
+
+type attachment struct {
+	hook    Hook
+	started bool
+}
+
+// Attaches a component to the default Runner's run phase. See Runner.Attach().
+func Attach(h Hook) (detach func()) {
+	return defaultRunner.Attach(h)
+}
+
+// Starts h with the run-phase context and keeps it running until detach is called or shutdown begins, for components
+// which come and go at runtime (ie plugins, or listeners added by a config reload).
+//
+// detach stops h under a context bounded by WithPerShutdownTimeout() (or WithShutdownTimeout() otherwise), abandoning
+// Stop() if it ignores its context. It is safe to call more than once. If Start() or Stop() returns an error, it is
+// logged and passed to the error reporter; an error from Start() also triggers shutdown, same as Go().
+//
+// Components which are still attached once shutdown begins are stopped in reverse attach order before the shutdown
+// functions run, and their errors are reported in ExitReason.ErrsShutdown. Components attached before startup completes
+// are started once it does, and are discarded if startup fails. Components attached after shutdown has begun are not
+// started.
+func (r *Runner) Attach(h Hook) (detach func()) {
+	a := &attachment{hook: h}
+
+	r.attMu.Lock()
+	defer r.attMu.Unlock()
+
+	switch {
+	case r.attClosed, r.attCtx != nil && r.attCtx.Err() != nil:
+		return nop
+	case r.attCtx == nil:
+		r.attached = append(r.attached, a)
+	default:
+		r.attached = append(r.attached, a)
+		r.startAttached(a)
+	}
+
+	var once sync.Once
+	return func() {
+		once.Do(func() { r.detach(a) })
+	}
+}
+
+// Must be called with attMu held. Start() is called with attMu held, so shutdown cannot miss a component which is
+// being started.
+func (r *Runner) startAttached(a *attachment) {
+	if err := r.attConfig.recoverPanics([]Func{a.hook.Start})[0](r.attCtx); err != nil {
+		r.attached = slices.DeleteFunc(r.attached, func(att *attachment) bool { return att == a })
+		r.attConfig.log(slog.LevelError, "attached component failed to start", slog.Any("err", err))
+		if r.attCtx.Err() == nil {
+			r.Shutdown(err)
+		} else {
+			r.recordRuntime(err)
+		}
+		return
+	}
+	a.started = true
+}
+
+func (r *Runner) detach(a *attachment) {
+	r.attMu.Lock()
+	i := slices.Index(r.attached, a)
+	if i >= 0 {
+		r.attached = slices.Delete(r.attached, i, i+1)
+	}
+	config := r.attConfig
+	r.attMu.Unlock()
+
+	if i < 0 || !a.started {
+		return
+	}
+
+	d := config.perShutdownTimeout
+	if d <= 0 {
+		d = config.shutdownTimeout
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	if d > 0 {
+		cancel()
+		ctx, cancel = config.withTimeoutCause(context.Background(), d, ErrShutdownFuncTimeout)
+	}
+	defer cancel()
+
+	stop := config.recoverPanics([]Func{a.hook.Stop})[0]
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- stop(ctx)
+	}()
+
+	var err error
+	select {
+	case err = <-errCh:
+	case <-ctx.Done():
+		err = context.Cause(ctx)
+	}
+
+	if err != nil {
+		config.log(slog.LevelError, "attached component failed to stop", slog.Any("err", err))
+		config.reportErr(PhaseRuntime, err)
+	}
+}
+
+// Starts the components attached before the run phase, and any subsequently attached ones, under ctx.
+func (r *Runner) beginAttach(ctx context.Context, config *config) {
+	r.attMu.Lock()
+	defer r.attMu.Unlock()
+
+	r.attCtx, r.attConfig = ctx, config
+	for _, a := range slices.Clone(r.attached) {
+		if ctx.Err() != nil {
+			break
+		}
+		r.startAttached(a)
+	}
+}
+
+// Stops accepting components and stops the attached ones in reverse attach order under ctx.
+func (r *Runner) endAttach(ctx context.Context) []error {
+	r.attMu.Lock()
+	attached, config := r.attached, r.attConfig
+	r.attached, r.attClosed = nil, true
+	r.attMu.Unlock()
+
+	var errs []error
+	for i := len(attached) - 1; i >= 0; i-- {
+		if !attached[i].started {
+			continue
+		}
+		if err := config.recoverPanics([]Func{attached[i].hook.Stop})[0](ctx); err != nil {
+			errs = append(errs, attribute("attached", i, err))
+		}
+	}
+	return errs
+}
+
+// Discards any components which were never started once the lifecycle ends, and accepts components for the next one.
+func (r *Runner) resetAttach() {
+	r.attMu.Lock()
+	defer r.attMu.Unlock()
+
+	r.attCtx, r.attConfig = nil, nil
+	r.attached, r.attClosed = nil, false
+}
@@ -0,0 +1,106 @@
+package graceful
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+)
+
+// Records the Start() and Stop() calls of named components.
+type attachLog struct {
+	mu    sync.Mutex
+	calls []string
+}
+
+func (al *attachLog) hook(name string) Hook {
+	record := func(call string) Func {
+		return func(ctx context.Context) error {
+			al.mu.Lock()
+			defer al.mu.Unlock()
+			al.calls = append(al.calls, call+" "+name)
+			return nil
+		}
+	}
+	return NewHook(record("start"), record("stop"))
+}
+
+func (al *attachLog) get() []string {
+	al.mu.Lock()
+	defer al.mu.Unlock()
+	return slices.Clone(al.calls)
+}
+
+func TestAttach(t *testing.T) {
+	r := New()
+	al := &attachLog{}
+
+	// Attached before the run phase, so started once startup completes.
+	r.Attach(al.hook("early"))
+
+	run := func(ctx context.Context) error {
+		detach := r.Attach(al.hook("plugin"))
+		detach()
+		detach()
+
+		r.Attach(al.hook("a"))
+		r.Attach(al.hook("b"))
+		r.Stop()
+		return nil
+	}
+	shutdown := func(ctx context.Context) error {
+		// Too late to be started.
+		r.Attach(al.hook("late"))
+		return nil
+	}
+
+	er := r.Start(nil, []Func{shutdown}, WithRun(run))
+	if er.HasErrors() {
+		t.Fatalf("unexpected errors: %v", er)
+	}
+
+	want := []string{"start early", "start plugin", "stop plugin", "start a", "start b", "stop b", "stop a", "stop early"}
+	if got := al.get(); !slices.Equal(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+}
+
+func TestAttachStartError(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	r := New()
+	run := func(ctx context.Context) error {
+		r.Attach(NewHook(func(ctx context.Context) error { return errBoom }, nil))
+		<-ctx.Done()
+		return nil
+	}
+
+	er := r.Start(nil, nil, WithRun(run))
+	if !errors.Is(er.ErrRuntime, errBoom) {
+		t.Fatalf("expected the start error to trigger shutdown, got %v", er.ErrRuntime)
+	}
+}
+
+func TestAttachDiscardedOnStartupFailure(t *testing.T) {
+	errBoom := errors.New("boom")
+	al := &attachLog{}
+
+	r := New()
+	r.Attach(al.hook("early"))
+	r.Start([]Func{func(ctx context.Context) error { return errBoom }}, nil)
+
+	if calls := al.get(); len(calls) != 0 {
+		t.Fatalf("expected the component not to be started, got %v", calls)
+	}
+
+	// The next lifecycle starts afresh.
+	r.Attach(al.hook("next"))
+	r.Start(nil, nil, WithRun(func(ctx context.Context) error {
+		r.Stop()
+		return nil
+	}))
+	if want := []string{"start next", "stop next"}; !slices.Equal(al.get(), want) {
+		t.Fatalf("expected %v, got %v", want, al.get())
+	}
+}
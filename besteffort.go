@@ -0,0 +1,67 @@
+package graceful
+
+import (
+	"context"
+	"errors"
+)
+
+// Wraps fn so its failure does not count as an error. A failed startup function does not abort startup, and its error
+// is reported in ExitReason.WarningsStartup. A failed shutdown or rollback function's error is reported in
+// ExitReason.WarningsShutdown instead of ExitReason.ErrsShutdown.
+//
+// Useful for non-essential steps, such as cache warm-ups or flushing metrics.
+func BestEffort(fn Func) Func {
+	return func(ctx context.Context) error {
+		if err := fn(ctx); err != nil {
+			return &bestEffortError{err: err}
+		}
+		return nil
+	}
+}
+
+// Marks an error returned by a function wrapped with BestEffort(). It is transparent otherwise.
+type bestEffortError struct {
+	err error
+}
+
+func (be *bestEffortError) Error() string {
+	return be.err.Error()
+}
+
+func (be *bestEffortError) Unwrap() error {
+	return be.err
+}
+
+// Reports whether err came from a function wrapped with BestEffort(). Unlike errors.As(), an error joining several
+// others (such as one returned by Multi()) only counts if every one of them is best effort, so a best effort failure
+// cannot demote the failure of a function it was combined with.
+func isBestEffort(err error) bool {
+	for err != nil {
+		switch e := err.(type) {
+		case *bestEffortError:
+			return true
+		case interface{ Unwrap() []error }:
+			errs := e.Unwrap()
+			for _, err := range errs {
+				if !isBestEffort(err) {
+					return false
+				}
+			}
+			return len(errs) > 0
+		}
+		err = errors.Unwrap(err)
+	}
+	return false
+}
+
+// Separates the errors of functions wrapped with BestEffort() from the others.
+func splitBestEffort(errs []error) (failures []error, warnings []error) {
+	for _, err := range errs {
+		if isBestEffort(err) {
+			warnings = append(warnings, err)
+		} else {
+			failures = append(failures, err)
+		}
+	}
+	return failures, warnings
+}
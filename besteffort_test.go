@@ -0,0 +1,50 @@
+package graceful
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestBestEffort(t *testing.T) {
+	errWarm, errFlush := errors.New("warm-up failed"), errors.New("flush failed")
+	r := New()
+	r.Shutdown(nil)
+
+	warmUp := BestEffort(func(ctx context.Context) error { return errWarm })
+	flush := BestEffort(func(ctx context.Context) error { return errFlush })
+
+	er := r.Start([]Func{warmUp}, []Func{flush})
+
+	if er.ErrStartup != nil || !er.Clean() {
+		t.Fatalf("expected best-effort failures not to count as errors, got %v", er)
+	}
+	if len(er.WarningsStartup) != 1 || !errors.Is(er.WarningsStartup[0], errWarm) {
+		t.Fatalf("expected the startup warning, got %v", er.WarningsStartup)
+	}
+	if len(er.WarningsShutdown) != 1 || !errors.Is(er.WarningsShutdown[0], errFlush) {
+		t.Fatalf("expected the shutdown warning, got %v", er.WarningsShutdown)
+	}
+}
+
+func TestBestEffortCombined(t *testing.T) {
+	errWarm, errBoom := errors.New("warm-up failed"), errors.New("boom")
+	warmUp := BestEffort(func(ctx context.Context) error { return errWarm })
+	fail := func(ctx context.Context) error { return errBoom }
+
+	// A best-effort failure must not demote the failure of a function it is combined with.
+	er := New().Start([]Func{Multi(warmUp, fail)}, nil)
+	if !errors.Is(er.ErrStartup, errBoom) || len(er.WarningsStartup) != 0 {
+		t.Fatalf("expected the combined failure to abort startup, got %v, warnings %v", er.ErrStartup, er.WarningsStartup)
+	}
+
+	r := New()
+	r.Shutdown(nil)
+	er = r.Start(nil, []Func{Multi(warmUp, fail), Multi(warmUp, warmUp)})
+	if len(er.ErrsShutdown) != 1 || !errors.Is(er.ErrsShutdown[0], errBoom) {
+		t.Fatalf("expected only the combined failure as a shutdown error, got %v", er.ErrsShutdown)
+	}
+	if len(er.WarningsShutdown) != 1 || !errors.Is(er.WarningsShutdown[0], errWarm) {
+		t.Fatalf("expected the functions which all failed best effort as a warning, got %v", er.WarningsShutdown)
+	}
+}
@@ -0,0 +1,88 @@
+package graceful
+
+import (
+	"context"
+	"strings"
+)
+
+// Classifies why the lifecycle ended. Values can be combined with | to match several causes in OnlyOn() and SkipOn().
+type ExitCause int
+
+const (
+	CauseSignal         ExitCause = 1 << iota // An OS signal was received.
+	CauseStartupFailure                       // A startup function failed or the startup timeout elapsed.
+	CauseContext                              // The parent context was canceled.
+	CauseRuntimeError                         // A runtime error was reported via Shutdown(), a run function, or Go().
+	CauseClean                                // Shutdown was requested without an error, ie Shutdown(nil) or Stop().
+)
+
+func (ec ExitCause) String() string {
+	names := []string{"Signal", "StartupFailure", "Context", "RuntimeError", "Clean"}
+
+	var set []string
+	for i, name := range names {
+		if ec&(1<<i) != 0 {
+			set = append(set, name)
+		}
+	}
+	if len(set) == 0 {
+		return "Unknown"
+	}
+	return strings.Join(set, "|")
+}
+
+// Returns the cause of the exit, following the same precedence as Context(): an OS signal, then a startup error, the
+// parent context, and a runtime error.
+func (er *ExitReason) Cause() ExitCause {
+	switch {
+	case er.OsSignal != nil:
+		return CauseSignal
+	case er.ErrStartup != nil:
+		return CauseStartupFailure
+	case er.ErrContext != nil:
+		return CauseContext
+	case er.ErrRuntime != nil:
+		return CauseRuntimeError
+	default:
+		return CauseClean
+	}
+}
+
+type exitCauseKey struct{}
+
+// Returns why shutdown is happening, given the context passed to a shutdown or rollback function. Returns 0 if ctx was
+// not passed to a shutdown or rollback function.
+func ExitCauseFrom(ctx context.Context) ExitCause {
+	ec, _ := ctx.Value(exitCauseKey{}).(ExitCause)
+	return ec
+}
+
+// Returns a shutdown (or rollback) function which only runs fn if the exit cause matches causes, ie a function which
+// reverts a migration only when startup failed:
+//
+//	graceful.OnlyOn(graceful.CauseStartupFailure, revertMigration)
+//
+// fn is skipped if ctx was not passed to a shutdown or rollback function.
+func OnlyOn(causes ExitCause, fn Func) Func {
+	return func(ctx context.Context) error {
+		if ExitCauseFrom(ctx)&causes == 0 {
+			return nil
+		}
+		return fn(ctx)
+	}
+}
+
+// Returns a shutdown (or rollback) function which runs fn unless the exit cause matches causes, ie a function which
+// flushes a metrics buffer except when startup failed:
+//
+//	graceful.SkipOn(graceful.CauseStartupFailure, flushMetrics)
+//
+// fn is run if ctx was not passed to a shutdown or rollback function.
+func SkipOn(causes ExitCause, fn Func) Func {
+	return func(ctx context.Context) error {
+		if ExitCauseFrom(ctx)&causes != 0 {
+			return nil
+		}
+		return fn(ctx)
+	}
+}
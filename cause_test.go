@@ -0,0 +1,93 @@
+package graceful
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"syscall"
+	"testing"
+)
+
+func TestExitCause(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	tests := []struct {
+		name string
+		er   *ExitReason
+		want ExitCause
+	}{
+		{"clean", &ExitReason{}, CauseClean},
+		{"signal", &ExitReason{OsSignal: syscall.SIGTERM, ErrRuntime: errBoom}, CauseSignal},
+		{"startup failure", &ExitReason{ErrStartup: errBoom, ErrContext: context.Canceled}, CauseStartupFailure},
+		{"context", &ExitReason{ErrContext: context.Canceled}, CauseContext},
+		{"runtime error", &ExitReason{ErrRuntime: errBoom}, CauseRuntimeError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.er.Cause(); got != tt.want {
+				t.Fatalf("expected %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestExitCauseString(t *testing.T) {
+	if s := (CauseSignal | CauseClean).String(); s != "Signal|Clean" {
+		t.Fatalf("expected Signal|Clean, got %s", s)
+	}
+	if s := ExitCause(0).String(); s != "Unknown" {
+		t.Fatalf("expected Unknown, got %s", s)
+	}
+}
+
+func TestOnlyOnSkipOn(t *testing.T) {
+	var ran []string
+	record := func(name string) Func {
+		return func(ctx context.Context) error {
+			ran = append(ran, name)
+			return nil
+		}
+	}
+	shutdownFns := []Func{
+		OnlyOn(CauseStartupFailure, record("only on failure")),
+		SkipOn(CauseStartupFailure, record("skip on failure")),
+		OnlyOn(CauseClean|CauseSignal, record("only on clean or signal")),
+	}
+
+	r := New()
+	r.Shutdown(nil)
+	r.Start(nil, shutdownFns)
+
+	expected := []string{"skip on failure", "only on clean or signal"}
+	if !slices.Equal(ran, expected) {
+		t.Fatalf("expected %v, got %v", expected, ran)
+	}
+
+	// Outside of shutdown, OnlyOn() skips and SkipOn() runs.
+	ran = nil
+	for _, fn := range shutdownFns {
+		fn(context.Background())
+	}
+	if !slices.Equal(ran, []string{"skip on failure"}) {
+		t.Fatalf("expected only SkipOn() to run outside of shutdown, got %v", ran)
+	}
+}
+
+func TestOnlyOnRollback(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	reverted := false
+	revert := OnlyOn(CauseStartupFailure, func(ctx context.Context) error {
+		reverted = true
+		return nil
+	})
+	ok := func(ctx context.Context) error { return nil }
+	fail := func(ctx context.Context) error { return errBoom }
+
+	New().Start([]Func{ok, fail}, nil, WithStartupRollback([]Func{revert}))
+
+	if !reverted {
+		t.Fatalf("expected the rollback function to run when startup failed")
+	}
+}
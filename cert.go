@@ -0,0 +1,60 @@
+package graceful
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"os"
+	"sync/atomic"
+	"time"
+)
+
+// Serves a TLS certificate which can be replaced without restarting, ie when it is renewed:
+//
+//	cr := graceful.NewCertReloader("tls.crt", "tls.key")
+//	srv := &http.Server{TLSConfig: &tls.Config{GetCertificate: cr.GetCertificate}}
+//	graceful.Start([]graceful.Func{cr.Load}, shutdownFns,
+//		graceful.WithReloadSignal(syscall.SIGHUP, cr.Load),
+//		graceful.WithRun(cr.Watch(graceful.Default(), syscall.SIGHUP, time.Minute)))
+//
+// Reload errors are reported like those of any reload function, so the previous certificate keeps being served.
+type CertReloader struct {
+	certFile string
+	keyFile  string
+	cert     atomic.Pointer[tls.Certificate]
+}
+
+// Creates a CertReloader for the PEM encoded key pair in certFile and keyFile. Nothing is loaded until Load() is called.
+func NewCertReloader(certFile, keyFile string) *CertReloader {
+	return &CertReloader{certFile: certFile, keyFile: keyFile}
+}
+
+// Loads the key pair, replacing the certificate served by GetCertificate() if it is valid. Use it as a startup function
+// and as a reload function.
+func (cr *CertReloader) Load(ctx context.Context) error {
+	cert, err := tls.LoadX509KeyPair(cr.certFile, cr.keyFile)
+	if err != nil {
+		return fmt.Errorf("failed to load certificate: %w", err)
+	}
+
+	cr.cert.Store(&cert)
+	return nil
+}
+
+// Implements tls.Config.GetCertificate.
+func (cr *CertReloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	cert := cr.cert.Load()
+	if cert == nil {
+		return nil, errors.New("no certificate loaded")
+	}
+	return cert, nil
+}
+
+// Returns a run function (see WithRun()) which checks the key pair files every interval and delivers sig to r (see
+// Runner.Signal()) when either has changed, so the reload functions registered for sig run as if it had been received
+// from the OS. sig must be registered with WithReloadSignal() with Load() among its functions. Use graceful.Default()
+// for the package-level functions. interval is measured by the Clock given to WithClock(), if any.
+func (cr *CertReloader) Watch(r *Runner, sig os.Signal, interval time.Duration) Func {
+	return watchFiles(r, sig, interval, cr.certFile, cr.keyFile)
+}
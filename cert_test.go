@@ -0,0 +1,136 @@
+package graceful
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// Writes a self-signed key pair for commonName to certFile and keyFile.
+func writeKeyPair(t *testing.T, certFile, keyFile, commonName string) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func commonName(t *testing.T, cr *CertReloader) string {
+	t.Helper()
+
+	cert, err := cr.GetCertificate(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return leaf.Subject.CommonName
+}
+
+func TestCertReloader(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key")
+	cr := NewCertReloader(certFile, keyFile)
+
+	if _, err := cr.GetCertificate(nil); err == nil {
+		t.Fatal("expected an error before a certificate is loaded")
+	}
+	if err := cr.Load(context.Background()); err == nil {
+		t.Fatal("expected an error for missing files")
+	}
+
+	writeKeyPair(t, certFile, keyFile, "first")
+	if err := cr.Load(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	// An invalid key pair keeps the previous certificate.
+	if err := os.WriteFile(keyFile, []byte("garbage"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := cr.Load(context.Background()); err == nil {
+		t.Fatal("expected an error for an invalid key pair")
+	}
+	if cn := commonName(t, cr); cn != "first" {
+		t.Fatalf("expected the previous certificate to be kept, got %q", cn)
+	}
+
+	writeKeyPair(t, certFile, keyFile, "second")
+	if err := cr.Load(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if cn := commonName(t, cr); cn != "second" {
+		t.Fatalf("expected the reloaded certificate, got %q", cn)
+	}
+}
+
+func TestCertReloaderWatch(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key")
+	cr := NewCertReloader(certFile, keyFile)
+
+	r := New()
+	reload := testSignal("reload")
+	sigs := make(chan os.Signal, 1)
+	r.notify(sigs, reload)
+	defer r.stopNotify(sigs)
+
+	clock := &tickClock{ticks: make(chan time.Time)}
+	ctx, cancel := context.WithCancel(context.WithValue(context.Background(), clockKey{}, Clock(clock)))
+	done := make(chan error)
+	go func() { done <- cr.Watch(r, reload, time.Minute)(ctx) }()
+
+	// Unchanged files do not trigger a reload.
+	clock.ticks <- time.Now()
+	select {
+	case <-sigs:
+		t.Fatal("expected no reload without a change")
+	default:
+	}
+
+	writeKeyPair(t, certFile, keyFile, "new")
+	clock.ticks <- time.Now()
+	select {
+	case <-sigs:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the change to trigger a reload")
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatalf("expected nil once ctx is done, got %v", err)
+	}
+}
@@ -0,0 +1,49 @@
+package graceful
+
+import (
+	"errors"
+	"time"
+)
+
+// Returned by lifecycle functions which WithChaos() made fail.
+var ErrChaos = errors.New("chaos: injected failure")
+
+// Selects the faults injected by WithChaos(). Each function draws its faults independently.
+type ChaosConfig struct {
+	// Probability (0 to 1) that a function is delayed by a random duration of up to MaxDelay before it runs. The delay
+	// ends early if the function's context is done.
+	DelayProbability float64
+	MaxDelay         time.Duration
+
+	// Probability (0 to 1) that a function returns ErrChaos instead of running.
+	ErrorProbability float64
+
+	// Phases faults are injected into: "startup", "shutdown", and/or "rollback". Default: all of them.
+	Phases []string
+}
+
+// Injects random delays and failures into the startup, shutdown, and rollback functions, to verify that an application
+// copes with slow or failing lifecycle functions. Faults are deterministic for a given seed: the same function (by
+// phase and position) always draws the same faults, regardless of scheduling.
+//
+// Only takes effect in binaries built with the graceful_chaos build tag (ie go test -tags graceful_chaos), so it can
+// never affect a production build. Otherwise the option is validated and ignored. Default: no faults.
+func WithChaos(seed uint64, cc ChaosConfig) Option {
+	return func(config *config) error {
+		if cc.DelayProbability < 0 || cc.DelayProbability > 1 || cc.ErrorProbability < 0 || cc.ErrorProbability > 1 {
+			return invalidOption("chaos probabilities must be between 0 and 1")
+		}
+		if cc.DelayProbability > 0 && cc.MaxDelay <= 0 {
+			return invalidOption("chaos max delay must be positive")
+		}
+		if chaosEnabled {
+			config.chaos = &chaosState{seed: seed, config: cc}
+		}
+		return nil
+	}
+}
+
+type chaosState struct {
+	seed   uint64
+	config ChaosConfig
+}
@@ -0,0 +1,9 @@
+//go:build !graceful_chaos
+
+package graceful
+
+const chaosEnabled = false
+
+func (c *config) injectChaos(phase string, fns []Func) []Func {
+	return fns
+}
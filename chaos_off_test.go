@@ -0,0 +1,20 @@
+//go:build !graceful_chaos
+
+package graceful
+
+import (
+	"context"
+	"testing"
+)
+
+func TestChaosIgnoredWithoutBuildTag(t *testing.T) {
+	ok := func(ctx context.Context) error { return nil }
+
+	r := New()
+	r.Shutdown(nil)
+	er := r.Start([]Func{ok}, []Func{ok}, WithChaos(1, ChaosConfig{ErrorProbability: 1}))
+
+	if er.ErrStartup != nil || len(er.ErrsShutdown) != 0 {
+		t.Fatalf("expected no injected faults, got %v and %v", er.ErrStartup, er.ErrsShutdown)
+	}
+}
@@ -0,0 +1,56 @@
+//go:build graceful_chaos
+
+package graceful
+
+import (
+	"context"
+	"hash/fnv"
+	"log/slog"
+	"math/rand/v2"
+	"slices"
+	"time"
+)
+
+const chaosEnabled = true
+
+// Wraps fns so they draw faults according to WithChaos().
+func (c *config) injectChaos(phase string, fns []Func) []Func {
+	if c.chaos == nil {
+		return fns
+	}
+	if phases := c.chaos.config.Phases; len(phases) > 0 && !slices.Contains(phases, phase) {
+		return fns
+	}
+
+	h := fnv.New64a()
+	h.Write([]byte(phase))
+	phaseHash := h.Sum64()
+
+	wrapped := make([]Func, len(fns))
+	for i, fn := range fns {
+		// A source per function keeps the draws independent of the order functions run in.
+		rng := rand.New(rand.NewPCG(c.chaos.seed, phaseHash+uint64(i)))
+		delay := rng.Float64() < c.chaos.config.DelayProbability
+		fail := rng.Float64() < c.chaos.config.ErrorProbability
+		var d time.Duration
+		if delay {
+			d = time.Duration(rng.Int64N(int64(c.chaos.config.MaxDelay)))
+		}
+
+		wrapped[i] = func(ctx context.Context) error {
+			if delay {
+				c.log(slog.LevelWarn, "chaos: delaying "+phase+" function", slog.Int("index", i), slog.Duration("delay", d))
+				select {
+				case <-c.after(d):
+				case <-ctx.Done():
+				}
+			}
+			if fail {
+				c.log(slog.LevelWarn, "chaos: failing "+phase+" function", slog.Int("index", i))
+				return ErrChaos
+			}
+			return fn(ctx)
+		}
+	}
+	return wrapped
+}
@@ -0,0 +1,56 @@
+//go:build graceful_chaos
+
+package graceful
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestChaosFailures(t *testing.T) {
+	ran := false
+	fn := func(ctx context.Context) error {
+		ran = true
+		return nil
+	}
+
+	er := New().Start([]Func{fn}, nil, WithChaos(1, ChaosConfig{ErrorProbability: 1}))
+
+	if !errors.Is(er.ErrStartup, ErrChaos) || ran {
+		t.Fatalf("expected ErrChaos instead of running the function, got ran=%v (%v)", ran, er.ErrStartup)
+	}
+}
+
+func TestChaosPhases(t *testing.T) {
+	ok := func(ctx context.Context) error { return nil }
+
+	r := New()
+	r.Shutdown(nil)
+	er := r.Start([]Func{ok}, []Func{ok}, WithChaos(1, ChaosConfig{ErrorProbability: 1, Phases: []string{"shutdown"}}))
+
+	if er.ErrStartup != nil || len(er.ErrsShutdown) != 1 || !errors.Is(er.ErrsShutdown[0], ErrChaos) {
+		t.Fatalf("expected faults only in shutdown, got %v and %v", er.ErrStartup, er.ErrsShutdown)
+	}
+}
+
+func TestChaosDeterministic(t *testing.T) {
+	draw := func(seed uint64) []bool {
+		fns := make([]Func, 16)
+		for i := range fns {
+			fns[i] = func(ctx context.Context) error { return nil }
+		}
+
+		c := &config{chaos: &chaosState{seed: seed, config: ChaosConfig{ErrorProbability: 0.5}}}
+		var failed []bool
+		for _, fn := range c.injectChaos("startup", fns) {
+			failed = append(failed, fn(context.Background()) != nil)
+		}
+		return failed
+	}
+
+	if a, b := draw(7), draw(7); !slices.Equal(a, b) {
+		t.Fatalf("expected the same faults for the same seed, got %v and %v", a, b)
+	}
+}
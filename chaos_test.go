@@ -0,0 +1,26 @@
+package graceful
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestChaosValidation(t *testing.T) {
+	tests := []struct {
+		name string
+		cc   ChaosConfig
+	}{
+		{"negative probability", ChaosConfig{ErrorProbability: -0.1}},
+		{"probability above 1", ChaosConfig{DelayProbability: 1.5, MaxDelay: time.Second}},
+		{"delay without max", ChaosConfig{DelayProbability: 0.5}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := parseOptions(&config{}, []Option{WithChaos(1, tt.cc)}); !errors.Is(err, ErrInvalidOption) {
+				t.Fatalf("expected ErrInvalidOption, got %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,149 @@
+package graceful
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+var (
+	// Returned by Spawn() when a child with the same name is already running.
+	ErrChildExists = errors.New("child already exists")
+
+	// Returned by Spawn() when the parent Runner is not starting or running.
+	ErrNotRunning = errors.New("runner is not starting or running")
+)
+
+type child struct {
+	runner *Runner
+	done   chan struct{}
+	er     *ExitReason
+}
+
+// Returns a startup function which starts a child lifecycle of the default Runner. See Runner.Child().
+func Child(name string, startupFns []Func, shutdownFns []Func, opts ...Option) Func {
+	return defaultRunner.Child(name, startupFns, shutdownFns, opts...)
+}
+
+// Returns a startup function which starts a child lifecycle of this Runner with Spawn(), so the parent's startup fails
+// if the child's does:
+//
+//	graceful.Start([]graceful.Func{
+//		db.Connect,
+//		graceful.Child("listener-a", listenerA.Startup, listenerA.Shutdown),
+//	}, []graceful.Func{db.Close})
+func (r *Runner) Child(name string, startupFns []Func, shutdownFns []Func, opts ...Option) Func {
+	return func(ctx context.Context) error {
+		_, err := r.Spawn(ctx, name, startupFns, shutdownFns, opts...)
+		return err
+	}
+}
+
+// Starts a child lifecycle of the default Runner. See Runner.Spawn().
+func Spawn(ctx context.Context, name string, startupFns []Func, shutdownFns []Func, opts ...Option) (*Runner, error) {
+	return defaultRunner.Spawn(ctx, name, startupFns, shutdownFns, opts...)
+}
+
+// Starts startupFns as the lifecycle of a new child Runner identified by name, and returns the child once its startup
+// has completed. May be called during startup or at runtime (ie to spin up a tenant). The child's Go(), Context(), etc
+// are scoped to the child.
+//
+// The child does not monitor OS signals; the parent stops it with StopChild() or when the parent shuts down, in which
+// case every running child is stopped concurrently before the parent's shutdown functions run (subject to the parent's
+// shutdown timeout) and their ExitReasons are reported in ExitReason.Children. A child which exits on its own (ie due to
+// a runtime error) does not affect the parent and is reported with EventChildExited.
+//
+// ctx only provides values to the child; its cancellation does not stop the child. If the child's startup fails, its
+// ExitReason is returned as the error.
+func (r *Runner) Spawn(ctx context.Context, name string, startupFns []Func, shutdownFns []Func, opts ...Option) (*Runner, error) {
+	c := &child{runner: New(), done: make(chan struct{})}
+	c.runner.child = true
+
+	r.childMu.Lock()
+	if s := r.State(); s != StateStarting && s != StateRunning {
+		r.childMu.Unlock()
+		return nil, fmt.Errorf("failed to spawn child %q: %w", name, ErrNotRunning)
+	}
+	if _, ok := r.children[name]; ok {
+		r.childMu.Unlock()
+		return nil, fmt.Errorf("failed to spawn child %q: %w", name, ErrChildExists)
+	}
+	if r.children == nil {
+		r.children = map[string]*child{}
+	}
+	r.children[name] = c
+	r.childMu.Unlock()
+
+	ready := make(chan struct{})
+	opts = append(opts[:len(opts):len(opts)], WithOnReady(func() { close(ready) }))
+
+	go func() {
+		c.er = c.runner.StartContext(context.WithoutCancel(ctx), startupFns, shutdownFns, opts...)
+		close(c.done)
+
+		r.childMu.Lock()
+		if r.children[name] == c {
+			delete(r.children, name)
+		}
+		r.childMu.Unlock()
+
+		r.emit(Event{Type: EventChildExited, Name: name, ExitReason: c.er})
+	}()
+
+	select {
+	case <-ready:
+		return c.runner, nil
+	case <-c.done:
+		return nil, c.er
+	}
+}
+
+// Stops the child started with Spawn() under name and returns its ExitReason once it has exited. Returns nil if there
+// is no such child.
+func StopChild(name string) *ExitReason {
+	return defaultRunner.StopChild(name)
+}
+
+// Same as the package-level StopChild(), but scoped to this Runner.
+func (r *Runner) StopChild(name string) *ExitReason {
+	r.childMu.Lock()
+	c, ok := r.children[name]
+	delete(r.children, name)
+	r.childMu.Unlock()
+
+	if !ok {
+		return nil
+	}
+
+	c.runner.Stop()
+	<-c.done
+	return c.er
+}
+
+// Stops every running child concurrently and waits for them until ctx is done. Returns the ExitReasons of the children
+// which exited, and ErrShutdownTimeout if any did not.
+func (r *Runner) stopChildren(ctx context.Context) (map[string]*ExitReason, error) {
+	r.childMu.Lock()
+	children := r.children
+	r.children = nil
+	r.childMu.Unlock()
+
+	if len(children) == 0 {
+		return nil, nil
+	}
+
+	for _, c := range children {
+		c.runner.ShutdownWithCause(nil, "parent shutdown")
+	}
+
+	ers := make(map[string]*ExitReason, len(children))
+	for name, c := range children {
+		select {
+		case <-c.done:
+			ers[name] = c.er
+		case <-ctx.Done():
+			return ers, ErrShutdownTimeout
+		}
+	}
+	return ers, nil
+}
@@ -0,0 +1,86 @@
+package graceful
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"testing"
+)
+
+func TestSpawn(t *testing.T) {
+	r := New()
+
+	var childShutdown bool
+	var stopped *ExitReason
+	run := func(ctx context.Context) error {
+		if _, err := r.Spawn(ctx, "a", nil, nil); err != nil {
+			return err
+		}
+		if _, err := r.Spawn(ctx, "a", nil, nil); !errors.Is(err, ErrChildExists) {
+			return errors.New("expected ErrChildExists for a duplicate name")
+		}
+
+		// A stopped child is not reported by the parent's shutdown.
+		stopped = r.StopChild("a")
+		if r.StopChild("a") != nil {
+			return errors.New("expected no ExitReason for a child which is not running")
+		}
+
+		shutdown := func(ctx context.Context) error {
+			childShutdown = true
+			return nil
+		}
+		if _, err := r.Spawn(ctx, "b", nil, []Func{shutdown}); err != nil {
+			return err
+		}
+		r.Stop()
+		return nil
+	}
+
+	er := r.Start(nil, nil, WithRun(run))
+	if er.HasErrors() {
+		t.Fatalf("unexpected errors: %v", er)
+	}
+	if stopped == nil || stopped.HasErrors() {
+		t.Fatalf("expected StopChild() to return the child's ExitReason, got %v", stopped)
+	}
+	if !childShutdown {
+		t.Fatal("expected the parent's shutdown to stop the child")
+	}
+	if len(er.Children) != 1 || er.Children["b"] == nil {
+		t.Fatalf("expected the running child's ExitReason, got %v", er.Children)
+	}
+
+	if _, err := r.Spawn(context.Background(), "c", nil, nil); !errors.Is(err, ErrNotRunning) {
+		t.Fatalf("expected ErrNotRunning once the parent has exited, got %v", err)
+	}
+}
+
+func TestSpawnStartupFailure(t *testing.T) {
+	errBoom := errors.New("boom")
+	failing := func(ctx context.Context) error { return errBoom }
+
+	r := New()
+	er := r.Start([]Func{r.Child("a", []Func{failing}, nil)}, nil)
+	if !errors.Is(er.ErrStartup, errBoom) {
+		t.Fatalf("expected the child's startup error to fail the parent's startup, got %v", er.ErrStartup)
+	}
+}
+
+func TestChildrenJSON(t *testing.T) {
+	er := &ExitReason{Children: map[string]*ExitReason{"a": {ErrRuntime: errors.New("boom"), OsSignal: os.Interrupt}}}
+
+	b, err := json.Marshal(er)
+	if err != nil {
+		t.Fatal(err)
+	}
+	restored := &ExitReason{}
+	if err := json.Unmarshal(b, restored); err != nil {
+		t.Fatal(err)
+	}
+	c := restored.Children["a"]
+	if c == nil || c.ErrRuntime == nil || c.ErrRuntime.Error() != "boom" || c.OsSignal != os.Interrupt {
+		t.Fatalf("expected the child's ExitReason to round trip, got %v", restored.Children)
+	}
+}
@@ -0,0 +1,135 @@
+package graceful
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Source of time for timeouts, the shutdown delay, restart backoff, and step timings. See WithClock().
+type Clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+}
+
+// Timeouts, the shutdown delay, restart backoff, Every() intervals, and step timings use c instead of the system clock,
+// so tests can advance time deterministically. See the gracefultest package for a fake implementation. Default: the
+// system clock.
+func WithClock(c Clock) Option {
+	return func(config *config) error {
+		config.clock = c
+		return nil
+	}
+}
+
+type clockKey struct{}
+
+// Same as time.After(), but measured by the Clock given to WithClock() if ctx belongs to a lifecycle which has one.
+// For helpers such as Every() which only see the context.
+func afterFrom(ctx context.Context, d time.Duration) <-chan time.Time {
+	if c, ok := ctx.Value(clockKey{}).(Clock); ok {
+		return c.After(d)
+	}
+	return time.After(d)
+}
+
+// Same as time.Now(), but read from the Clock given to WithClock() if ctx belongs to a lifecycle which has one.
+func nowFrom(ctx context.Context) time.Time {
+	if c, ok := ctx.Value(clockKey{}).(Clock); ok {
+		return c.Now()
+	}
+	return time.Now()
+}
+
+func (c *config) now() time.Time {
+	if c.clock == nil {
+		return time.Now()
+	}
+	return c.clock.Now()
+}
+
+func (c *config) since(t time.Time) time.Duration {
+	return c.now().Sub(t)
+}
+
+func (c *config) sleep(d time.Duration) {
+	<-c.after(d)
+}
+
+func (c *config) after(d time.Duration) <-chan time.Time {
+	if c.clock == nil {
+		return time.After(d)
+	}
+	return c.clock.After(d)
+}
+
+// Same as context.WithTimeout(), but measured by the configured clock.
+func (c *config) withTimeout(ctx context.Context, d time.Duration) (context.Context, context.CancelFunc) {
+	return c.withTimeoutCause(ctx, d, context.DeadlineExceeded)
+}
+
+// Same as context.WithTimeoutCause(), but measured by the configured clock.
+func (c *config) withTimeoutCause(ctx context.Context, d time.Duration, cause error) (context.Context, context.CancelFunc) {
+	if c.clock == nil {
+		return context.WithTimeoutCause(ctx, d, cause)
+	}
+
+	// context.Cause() finds the cause of the inner context, while the error is the clockContext's own.
+	inner, innerCancel := context.WithCancelCause(ctx)
+	cc := &clockContext{Context: inner, done: make(chan struct{}), deadline: c.now().Add(d)}
+	after := c.clock.After(d)
+	go func() {
+		select {
+		case <-ctx.Done():
+			cc.cancel(ctx.Err())
+		case <-after:
+			innerCancel(cause)
+			cc.cancel(context.DeadlineExceeded)
+		case <-cc.done:
+		}
+	}()
+
+	return cc, func() {
+		innerCancel(context.Canceled)
+		cc.cancel(context.Canceled)
+	}
+}
+
+// A context canceled by a Clock rather than a runtime timer. It has its own done channel so contexts derived from it
+// observe its error rather than its parent's.
+type clockContext struct {
+	context.Context
+	done     chan struct{}
+	deadline time.Time // Measured by the Clock
+
+	mu  sync.Mutex
+	err error
+}
+
+func (cc *clockContext) Deadline() (time.Time, bool) {
+	if parent, ok := cc.Context.Deadline(); ok && parent.Before(cc.deadline) {
+		return parent, true
+	}
+	return cc.deadline, true
+}
+
+func (cc *clockContext) Done() <-chan struct{} {
+	return cc.done
+}
+
+func (cc *clockContext) Err() error {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+
+	return cc.err
+}
+
+func (cc *clockContext) cancel(err error) {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+
+	if cc.err == nil {
+		cc.err = err
+		close(cc.done)
+	}
+}
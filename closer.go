@@ -0,0 +1,35 @@
+package graceful
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+// Adapts c to a Func which closes it, so it can be used as a shutdown function. The context is ignored.
+func Closer(c io.Closer) Func {
+	return func(ctx context.Context) error {
+		return c.Close()
+	}
+}
+
+// Adapts cs to a single Func which closes each of them in order. Every Close() is called even if an earlier one fails,
+// and all errors are reported, combined with errors.Join().
+func Closers(cs ...io.Closer) Func {
+	return func(ctx context.Context) error {
+		var errs []error
+		for _, c := range cs {
+			if err := c.Close(); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		return errors.Join(errs...)
+	}
+}
+
+// Adapts a function which does not accept a context to a Func. The context is ignored.
+func NoContext(fn func() error) Func {
+	return func(ctx context.Context) error {
+		return fn()
+	}
+}
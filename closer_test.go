@@ -0,0 +1,38 @@
+package graceful
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+type closerFunc func() error
+
+func (f closerFunc) Close() error { return f() }
+
+func TestClosers(t *testing.T) {
+	errA, errB := errors.New("a"), errors.New("b")
+
+	var closed []int
+	closer := func(i int, err error) closerFunc {
+		return func() error {
+			closed = append(closed, i)
+			return err
+		}
+	}
+
+	err := Closers(closer(1, errA), closer(2, nil), closer(3, errB))(context.Background())
+	if !errors.Is(err, errA) || !errors.Is(err, errB) {
+		t.Fatalf("expected both errors to be reported, got %v", err)
+	}
+	if len(closed) != 3 {
+		t.Fatalf("expected every closer to be closed, got %v", closed)
+	}
+
+	if err := Closer(closer(4, errA))(context.Background()); err != errA {
+		t.Fatalf("expected Closer() to return the Close() error, got %v", err)
+	}
+	if err := NoContext(func() error { return errB })(context.Background()); err != errB {
+		t.Fatalf("expected NoContext() to return the function's error, got %v", err)
+	}
+}
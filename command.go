@@ -0,0 +1,112 @@
+package graceful
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"sync"
+	"sync/atomic"
+	"syscall"
+)
+
+// Adapts a subprocess to the lifecycle. Pass run to WithRun() and stop to the shutdown functions.
+//
+// run starts a copy of cmd and waits for it. If it exits before stop is called, even successfully, run returns an error
+// so shutdown is triggered and the exit is reported in ExitReason.ErrRuntime. cmd itself is never started, so run may
+// be called again by a later lifecycle (ie a restart by Supervise()). run does not start the subprocess once ctx is
+// done. Since only the exported fields are copied, cmd should be created with exec.Command() rather than
+// exec.CommandContext().
+//
+// stop sends SIGTERM to the subprocess started by the latest run and waits for it to exit. If the shutdown context is
+// done first, it is killed with SIGKILL and the context's error is returned. Use WithPerShutdownTimeout() to bound the
+// grace period.
+func Command(cmd *exec.Cmd) (run Func, stop Func) {
+	var mu sync.Mutex
+	var current *commandRun
+
+	run = func(ctx context.Context) error {
+		cr := &commandRun{cmd: cloneCmd(cmd), exited: make(chan struct{})}
+
+		// Shutdown cancels ctx before stop is called, so checking it under mu guarantees stop sees every subprocess.
+		mu.Lock()
+		if ctx.Err() != nil {
+			mu.Unlock()
+			return nil
+		}
+		// Started as a tracked child, so the reaper of WithInitMode() does not swallow its exit status.
+		wait, err := startChild(cr.cmd)
+		if err != nil {
+			mu.Unlock()
+			return fmt.Errorf("failed to start %s: %w", cmd.Path, err)
+		}
+		current = cr
+		mu.Unlock()
+
+		err = wait()
+		close(cr.exited)
+
+		if cr.stopping.Load() {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("%s exited unexpectedly: %w", cmd.Path, err)
+		}
+		return fmt.Errorf("%s exited unexpectedly", cmd.Path)
+	}
+
+	stop = func(ctx context.Context) error {
+		mu.Lock()
+		cr := current
+		mu.Unlock()
+
+		if cr == nil {
+			return nil
+		}
+		cr.stopping.Store(true)
+
+		select {
+		case <-cr.exited:
+			return nil
+		default:
+		}
+
+		if err := cr.cmd.Process.Signal(syscall.SIGTERM); err != nil {
+			cr.cmd.Process.Kill()
+		}
+
+		select {
+		case <-cr.exited:
+			return nil
+		case <-ctx.Done():
+			cr.cmd.Process.Kill()
+			<-cr.exited
+			return ctx.Err()
+		}
+	}
+
+	return run, stop
+}
+
+// A subprocess started by a single call of Command()'s run function.
+type commandRun struct {
+	cmd      *exec.Cmd
+	stopping atomic.Bool
+	exited   chan struct{}
+}
+
+// Returns an unstarted copy of cmd, since an exec.Cmd can only be started once.
+func cloneCmd(cmd *exec.Cmd) *exec.Cmd {
+	return &exec.Cmd{
+		Path:        cmd.Path,
+		Args:        cmd.Args,
+		Env:         cmd.Env,
+		Dir:         cmd.Dir,
+		Stdin:       cmd.Stdin,
+		Stdout:      cmd.Stdout,
+		Stderr:      cmd.Stderr,
+		ExtraFiles:  cmd.ExtraFiles,
+		SysProcAttr: cmd.SysProcAttr,
+		WaitDelay:   cmd.WaitDelay,
+		Err:         cmd.Err,
+	}
+}
@@ -0,0 +1,87 @@
+package graceful
+
+import (
+	"context"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+)
+
+func lookPath(t *testing.T, file string) string {
+	t.Helper()
+
+	path, err := exec.LookPath(file)
+	if err != nil {
+		t.Skipf("%s not found: %v", file, err)
+	}
+	return path
+}
+
+func TestCommandStop(t *testing.T) {
+	run, stop := Command(exec.Command(lookPath(t, "sleep"), "10"))
+
+	r := New()
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		r.Shutdown(nil)
+	}()
+
+	er := r.Start(nil, []Func{stop}, WithRun(run), WithShutdownTimeout(5*time.Second))
+	if er.ErrRuntime != nil || er.ErrsShutdown != nil {
+		t.Fatalf("expected a clean exit, got %v", er)
+	}
+}
+
+func TestCommandExitsUnexpectedly(t *testing.T) {
+	run, stop := Command(exec.Command(lookPath(t, "true")))
+
+	er := New().Start(nil, []Func{stop}, WithRun(run))
+	if er.ErrRuntime == nil || !strings.Contains(er.ErrRuntime.Error(), "exited unexpectedly") {
+		t.Fatalf("expected an unexpected exit, got %v", er.ErrRuntime)
+	}
+}
+
+func TestCommandReused(t *testing.T) {
+	run, stop := Command(exec.Command(lookPath(t, "true")))
+
+	// Each call starts a fresh copy of the command, so a second lifecycle must neither fail to start it nor panic.
+	for i := range 3 {
+		err := run(context.Background())
+		if err == nil || strings.Contains(err.Error(), "failed to start") {
+			t.Fatalf("run %d: expected an unexpected exit, got %v", i, err)
+		}
+		if err := stop(context.Background()); err != nil {
+			t.Fatalf("run %d: stop: %v", i, err)
+		}
+	}
+}
+
+func TestCommandNotStartedAfterShutdown(t *testing.T) {
+	run, stop := Command(exec.Command(lookPath(t, "sleep"), "10"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := run(ctx); err != nil {
+		t.Fatalf("expected run to return nil once ctx is done, got %v", err)
+	}
+	if err := stop(context.Background()); err != nil {
+		t.Fatalf("stop: %v", err)
+	}
+}
+
+func TestCommandInInitMode(t *testing.T) {
+	if !initModeSupported {
+		t.Skip("init mode is not supported")
+	}
+
+	stopReaper := startReaper()
+	defer stopReaper()
+
+	// The reaper must hand the exit status over rather than run reporting a failed wait.
+	run, _ := Command(exec.Command(lookPath(t, "sh"), "-c", "exit 3"))
+	if err := run(context.Background()); err == nil || !strings.HasSuffix(err.Error(), "exit status 3") {
+		t.Fatalf("expected exit status 3, got %v", err)
+	}
+}
@@ -0,0 +1,32 @@
+package graceful
+
+import "context"
+
+// Returns a function which runs fn only if cond reports true when it is called, so optional subsystems (ie a debug
+// server) can stay in the declared startup and shutdown functions:
+//
+//	graceful.If(cfg.DebugEnabled, debugServer.Start)
+//
+// cond is evaluated on every call, so the startup and shutdown functions of a subsystem agree if cond does not change
+// in between. Use Enabled() for a condition known up front.
+func If(cond func() bool, fn Func) Func {
+	return func(ctx context.Context) error {
+		if !cond() {
+			return nil
+		}
+		return fn(ctx)
+	}
+}
+
+// Same as If(), but runs fn only if cond reports false.
+func Unless(cond func() bool, fn Func) Func {
+	return If(func() bool { return !cond() }, fn)
+}
+
+// Returns fn if flag is true, otherwise a function which does nothing.
+func Enabled(flag bool, fn Func) Func {
+	if !flag {
+		return func(context.Context) error { return nil }
+	}
+	return fn
+}
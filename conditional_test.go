@@ -0,0 +1,45 @@
+package graceful
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestIf(t *testing.T) {
+	errFn := errors.New("fn")
+	fn := func(ctx context.Context) error { return errFn }
+
+	var enabled bool
+	cond := func() bool { return enabled }
+
+	tests := []struct {
+		name    string
+		fn      Func
+		enabled bool
+		want    error
+	}{
+		{"If false", If(cond, fn), false, nil},
+		{"If true", If(cond, fn), true, errFn},
+		{"Unless false", Unless(cond, fn), false, errFn},
+		{"Unless true", Unless(cond, fn), true, nil},
+	}
+	for _, tt := range tests {
+		enabled = tt.enabled
+		if err := tt.fn(context.Background()); err != tt.want {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.want, err)
+		}
+	}
+}
+
+func TestEnabled(t *testing.T) {
+	errFn := errors.New("fn")
+	fn := func(ctx context.Context) error { return errFn }
+
+	if err := Enabled(true, fn)(context.Background()); err != errFn {
+		t.Fatalf("expected fn to run when enabled, got %v", err)
+	}
+	if err := Enabled(false, fn)(context.Background()); err != nil {
+		t.Fatalf("expected nothing to run when disabled, got %v", err)
+	}
+}
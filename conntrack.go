@@ -0,0 +1,138 @@
+package graceful
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+)
+
+// Wraps a net.Listener to track the connections it accepts, so servers of custom protocols can be drained like
+// http.Server:
+//
+//	ct := graceful.NewConnTracker(ln, 5*time.Second)
+//	graceful.Start(nil, []graceful.Func{ct.Drain}, graceful.WithRun(func(ctx context.Context) error {
+//		return serve(ct) // Accept() from ct instead of ln
+//	}))
+type ConnTracker struct {
+	net.Listener
+	drainDeadline time.Duration
+
+	mu       sync.Mutex
+	conns    map[*trackedConn]struct{}
+	idle     chan struct{}
+	draining bool
+}
+
+// Creates a ConnTracker which accepts from ln. If drainDeadline is positive, Drain() sets a read and write deadline of
+// drainDeadline on every live connection so handlers blocked on I/O notice shutdown.
+func NewConnTracker(ln net.Listener, drainDeadline time.Duration) *ConnTracker {
+	idle := make(chan struct{})
+	close(idle)
+
+	return &ConnTracker{
+		Listener:      ln,
+		drainDeadline: drainDeadline,
+		conns:         map[*trackedConn]struct{}{},
+		idle:          idle,
+	}
+}
+
+// Accepts a connection from the wrapped listener and tracks it until it is closed. A connection accepted once Drain()
+// has begun is closed, and net.ErrClosed is returned.
+func (ct *ConnTracker) Accept() (net.Conn, error) {
+	conn, err := ct.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+
+	tc := &trackedConn{Conn: conn, ct: ct}
+
+	ct.mu.Lock()
+	defer ct.mu.Unlock()
+
+	// Checked under the same lock as the connection is tracked, so Drain() cannot miss a connection which raced with
+	// the listener being closed.
+	if ct.draining {
+		conn.Close()
+		return nil, net.ErrClosed
+	}
+
+	if len(ct.conns) == 0 {
+		ct.idle = make(chan struct{})
+	}
+	ct.conns[tc] = struct{}{}
+
+	return tc, nil
+}
+
+// Returns the number of live connections.
+func (ct *ConnTracker) Len() int {
+	ct.mu.Lock()
+	defer ct.mu.Unlock()
+
+	return len(ct.conns)
+}
+
+// A shutdown function which closes the listener, applies the drain deadline, and waits for every live connection to be
+// closed. If ctx is done first, the remaining connections are closed forcibly and the context's error is returned along
+// with their number.
+func (ct *ConnTracker) Drain(ctx context.Context) error {
+	ct.mu.Lock()
+	ct.draining = true
+	ct.Listener.Close()
+
+	if ct.drainDeadline > 0 {
+		deadline := time.Now().Add(ct.drainDeadline)
+		for tc := range ct.conns {
+			tc.SetDeadline(deadline)
+		}
+	}
+	idle := ct.idle
+	ct.mu.Unlock()
+
+	select {
+	case <-idle:
+		return nil
+	case <-ctx.Done():
+	}
+
+	ct.mu.Lock()
+	remaining := make([]*trackedConn, 0, len(ct.conns))
+	for tc := range ct.conns {
+		remaining = append(remaining, tc)
+	}
+	ct.mu.Unlock()
+
+	for _, tc := range remaining {
+		tc.Close()
+	}
+
+	return fmt.Errorf("%d connection(s) still open: %w", len(remaining), ctx.Err())
+}
+
+func (ct *ConnTracker) untrack(tc *trackedConn) {
+	ct.mu.Lock()
+	defer ct.mu.Unlock()
+
+	if _, ok := ct.conns[tc]; !ok {
+		return
+	}
+
+	delete(ct.conns, tc)
+	if len(ct.conns) == 0 {
+		close(ct.idle)
+	}
+}
+
+type trackedConn struct {
+	net.Conn
+	ct *ConnTracker
+}
+
+func (tc *trackedConn) Close() error {
+	err := tc.Conn.Close()
+	tc.ct.untrack(tc)
+	return err
+}
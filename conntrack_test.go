@@ -0,0 +1,100 @@
+package graceful
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestConnTrackerDrain(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("cannot listen: %v", err)
+	}
+	ct := NewConnTracker(ln, 0)
+
+	client, err := net.Dial("tcp", ln.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	conn, err := ct.Accept()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ct.Len() != 1 {
+		t.Fatalf("expected 1 connection, got %d", ct.Len())
+	}
+
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		conn.Close()
+	}()
+	if err := ct.Drain(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ct.Accept(); err == nil {
+		t.Fatal("expected the listener to be closed")
+	}
+}
+
+func TestConnTrackerDrainTimeout(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("cannot listen: %v", err)
+	}
+	ct := NewConnTracker(ln, 0)
+
+	client, err := net.Dial("tcp", ln.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+	if _, err := ct.Accept(); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	if err := ct.Drain(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected the remaining connection to be abandoned, got %v", err)
+	}
+	if ct.Len() != 0 {
+		t.Fatalf("expected the remaining connection to be closed, got %d", ct.Len())
+	}
+}
+
+func TestConnTrackerAcceptAfterDrain(t *testing.T) {
+	server, client := net.Pipe()
+	defer client.Close()
+
+	// The connection was accepted by the wrapped listener just before Drain() closed it.
+	ct := NewConnTracker(&pendingListener{conn: server}, 0)
+	if err := ct.Drain(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if conn, err := ct.Accept(); !errors.Is(err, net.ErrClosed) {
+		t.Fatalf("expected net.ErrClosed, got %v, %v", conn, err)
+	}
+	if ct.Len() != 0 {
+		t.Fatalf("expected the late connection not to be tracked, got %d", ct.Len())
+	}
+	if _, err := server.Write([]byte{0}); !errors.Is(err, io.ErrClosedPipe) {
+		t.Fatalf("expected the late connection to be closed, got %v", err)
+	}
+}
+
+// Returns conn from Accept() even once closed.
+type pendingListener struct {
+	net.Listener
+	conn net.Conn
+}
+
+func (pl *pendingListener) Accept() (net.Conn, error) { return pl.conn, nil }
+func (pl *pendingListener) Close() error              { return nil }
@@ -0,0 +1,82 @@
+package graceful
+
+import (
+	"context"
+	"os"
+)
+
+// The cause of Context() when shutdown was triggered by an OS signal.
+type SignalError struct {
+	Signal os.Signal
+}
+
+func (se *SignalError) Error() string {
+	return "received signal: " + se.Signal.String()
+}
+
+// Returns a context of the default Runner which is canceled the moment shutdown begins (or startup fails).
+//
+// context.Cause() reports why: a *SignalError, the runtime error passed to Shutdown(), the parent context's cause, or
+// the startup error. If shutdown was triggered without an error, the cause is context.Canceled. See CauseFrom().
+//
+// Useful for HTTP handlers and workers which should stop taking on new work once shutdown begins.
+func Context() context.Context {
+	return defaultRunner.Context()
+}
+
+// Same as the package-level Context(), but scoped to this Runner.
+func (r *Runner) Context() context.Context {
+	r.ctxMu.Lock()
+	defer r.ctxMu.Unlock()
+
+	if r.ctx == nil {
+		r.ctx, r.ctxCancel = context.WithCancelCause(context.Background())
+	}
+	return r.ctx
+}
+
+// Replaces the context if a previous lifecycle already canceled it.
+func (r *Runner) prepareContext() {
+	r.ctxMu.Lock()
+	defer r.ctxMu.Unlock()
+
+	if r.ctx == nil || r.ctx.Err() != nil {
+		r.ctx, r.ctxCancel = context.WithCancelCause(context.Background())
+	}
+}
+
+func (r *Runner) cancelContext(er *ExitReason) {
+	r.ctxMu.Lock()
+	defer r.ctxMu.Unlock()
+
+	r.ctxCancel(shutdownCause(er))
+}
+
+// Returns why the lifecycle is ending: a *SignalError, the startup error, the parent context's cause, or the runtime
+// error. Returns context.Canceled if shutdown was triggered without an error.
+func shutdownCause(er *ExitReason) error {
+	switch {
+	case er.OsSignal != nil:
+		return &SignalError{Signal: er.OsSignal}
+	case er.ErrStartup != nil:
+		return er.ErrStartup
+	case er.ErrContext != nil:
+		return er.ErrContext
+	case er.ErrRuntime != nil:
+		return er.ErrRuntime
+	default:
+		return context.Canceled
+	}
+}
+
+type causeKey struct{}
+
+// Returns why shutdown is happening, given the context passed to a shutdown or rollback function: a *SignalError, the
+// startup error (for rollback), the parent context's cause, or the runtime error. If shutdown was triggered without an
+// error, context.Canceled is returned.
+//
+// Returns nil if ctx was not passed to a shutdown or rollback function.
+func CauseFrom(ctx context.Context) error {
+	cause, _ := ctx.Value(causeKey{}).(error)
+	return cause
+}
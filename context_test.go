@@ -0,0 +1,127 @@
+package graceful
+
+import (
+	"context"
+	"errors"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestContextCanceledWhenShutdownBegins(t *testing.T) {
+	r := New()
+	errBoom := errors.New("boom")
+
+	stopped := make(chan error, 1)
+	run := func(ctx context.Context) error {
+		return errBoom
+	}
+	stop := func(ctx context.Context) error {
+		stopped <- context.Cause(r.Context())
+		return nil
+	}
+
+	r.Start(nil, []Func{stop}, WithRun(run))
+	if cause := <-stopped; !errors.Is(cause, errBoom) {
+		t.Fatalf("expected Context() to be canceled with the runtime error before the shutdown functions, got %v", cause)
+	}
+}
+
+func TestContextCauseIsSignal(t *testing.T) {
+	r := New()
+
+	run := func(ctx context.Context) error {
+		if err := signalSelf(syscall.SIGTERM); err != nil {
+			return err
+		}
+		<-ctx.Done()
+		return nil
+	}
+
+	r.Start(nil, nil, WithRun(run))
+
+	var se *SignalError
+	if cause := context.Cause(r.Context()); !errors.As(cause, &se) || se.Signal != syscall.SIGTERM {
+		t.Fatalf("expected a *SignalError, got %v", cause)
+	}
+}
+
+func TestContextCanceledWhenStartupFails(t *testing.T) {
+	r := New()
+	errBoom := errors.New("boom")
+
+	fail := func(ctx context.Context) error {
+		return errBoom
+	}
+
+	r.Start([]Func{fail}, nil)
+	if cause := context.Cause(r.Context()); !errors.Is(cause, errBoom) {
+		t.Fatalf("expected Context() to be canceled with the startup error, got %v", cause)
+	}
+}
+
+func TestContextReplacedForNextLifecycle(t *testing.T) {
+	r := New()
+	r.Shutdown(nil)
+	r.Start(nil, nil)
+
+	alive := make(chan bool, 1)
+	run := func(ctx context.Context) error {
+		alive <- r.Context().Err() == nil
+		return nil
+	}
+
+	r.Start(nil, nil, WithRun(run))
+	if !<-alive {
+		t.Fatal("expected a fresh Context() for the second lifecycle")
+	}
+}
+
+func TestContextCanceledBeforeShutdownDelay(t *testing.T) {
+	r := New()
+
+	observed := make(chan time.Duration, 1)
+	run := func(ctx context.Context) error {
+		r.Shutdown(nil)
+		start := time.Now()
+		<-r.Context().Done()
+		observed <- time.Since(start)
+		return nil
+	}
+
+	er := r.Start(nil, nil, WithRun(run), WithShutdownDelay(200*time.Millisecond))
+	if waited := <-observed; waited >= 200*time.Millisecond {
+		t.Fatalf("expected Context() to be canceled before the shutdown delay, waited %s", waited)
+	}
+	if er.Timing.ShutdownDelay != 200*time.Millisecond {
+		t.Fatalf("expected the shutdown delay to be recorded, got %s", er.Timing.ShutdownDelay)
+	}
+}
+
+func TestCauseFrom(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	var causes []error
+	record := func(ctx context.Context) error {
+		causes = append(causes, CauseFrom(ctx))
+		return nil
+	}
+
+	r := New()
+	r.Shutdown(errBoom)
+	r.Start(nil, []Func{record})
+
+	r.Shutdown(nil)
+	r.Start(nil, []Func{record})
+
+	// Rollback functions receive the startup error.
+	fail := func(ctx context.Context) error { return errBoom }
+	New().Start([]Func{record, fail}, nil, WithStartupRollback([]Func{record}))
+
+	if len(causes) != 4 || causes[0] != errBoom || causes[1] != context.Canceled || causes[2] != nil || !errors.Is(causes[3], errBoom) {
+		t.Fatalf("unexpected causes: %v", causes)
+	}
+	if CauseFrom(context.Background()) != nil {
+		t.Fatal("expected no cause outside of shutdown")
+	}
+}
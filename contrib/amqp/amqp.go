@@ -0,0 +1,87 @@
+// Package amqp adapts AMQP 0-9-1 (RabbitMQ) consumers to a graceful lifecycle, tearing them down in the order which
+// avoids losing or needlessly redelivering messages: cancel the consumer, process the deliveries already received,
+// then close the channel, then the connection.
+//
+// The adapter is defined against small interfaces satisfied by *amqp.Channel and *amqp.Connection from
+// github.com/rabbitmq/amqp091-go, so this package does not depend on it.
+package amqp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/bryhen/graceful"
+)
+
+// The subset of *amqp.Channel used by Consumer().
+type Channel interface {
+	Cancel(consumer string, noWait bool) error
+	Close() error
+}
+
+// The subset of *amqp.Connection used by Consumer().
+type Connection interface {
+	Close() error
+}
+
+// Adapts a consumer to the lifecycle, where deliveries was returned by ch.Consume() for the consumer tag consumer and D
+// is amqp.Delivery. Pass run to graceful.WithRun() and stop to the shutdown functions.
+//
+// run passes each delivery to handle, which is responsible for acknowledging it. When shutdown begins, the consumer is
+// canceled so the broker stops delivering, and the deliveries already received are still handled, with a context
+// which is not canceled then. If handle fails, the error is returned, which triggers shutdown.
+//
+// stop waits for run to return, then closes ch and then conn. Unacknowledged deliveries are requeued by the broker. If
+// the shutdown context is done first, ch and conn are closed anyway and the context's error is returned.
+func Consumer[D any](conn Connection, ch Channel, consumer string, deliveries <-chan D, handle func(ctx context.Context, d D) error) (run graceful.Func, stop graceful.Func) {
+	done := make(chan struct{})
+
+	run = func(ctx context.Context) error {
+		defer close(done)
+
+		dCtx := context.WithoutCancel(ctx)
+		ctxDone := ctx.Done()
+		canceled := false
+		for {
+			select {
+			case d, ok := <-deliveries:
+				if !ok {
+					if canceled {
+						return nil
+					}
+					return errors.New("deliveries closed unexpectedly")
+				}
+				if err := handle(dCtx, d); err != nil {
+					return err
+				}
+			case <-ctxDone:
+				// Keep reading until the client closes deliveries in response to the cancellation.
+				ctxDone = nil
+				canceled = true
+				if err := ch.Cancel(consumer, false); err != nil {
+					return fmt.Errorf("failed to cancel consumer: %w", err)
+				}
+			}
+		}
+	}
+
+	stop = func(ctx context.Context) error {
+		var err error
+		select {
+		case <-done:
+		case <-ctx.Done():
+			err = ctx.Err()
+		}
+
+		if cerr := ch.Close(); cerr != nil {
+			err = errors.Join(err, fmt.Errorf("failed to close channel: %w", cerr))
+		}
+		if cerr := conn.Close(); cerr != nil {
+			err = errors.Join(err, fmt.Errorf("failed to close connection: %w", cerr))
+		}
+		return err
+	}
+
+	return run, stop
+}
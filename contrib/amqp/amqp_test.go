@@ -0,0 +1,79 @@
+package amqp
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+)
+
+// Records the teardown order. Cancel() closes deliveries, as the client does once the broker confirms.
+type fakeBroker struct {
+	deliveries chan int
+	calls      []string
+}
+
+func (fb *fakeBroker) Cancel(consumer string, noWait bool) error {
+	fb.calls = append(fb.calls, "cancel "+consumer)
+	close(fb.deliveries)
+	return nil
+}
+
+func (fb *fakeBroker) Close() error {
+	fb.calls = append(fb.calls, "close")
+	return nil
+}
+
+type fakeConn struct {
+	fb *fakeBroker
+}
+
+func (fc *fakeConn) Close() error {
+	fc.fb.calls = append(fc.fb.calls, "close connection")
+	return nil
+}
+
+func TestConsumerTeardownOrder(t *testing.T) {
+	fb := &fakeBroker{deliveries: make(chan int, 2)}
+	fb.deliveries <- 1
+	fb.deliveries <- 2
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var handled []int
+	handle := func(dCtx context.Context, d int) error {
+		handled = append(handled, d)
+		if d == 1 {
+			cancel()
+		}
+		if dCtx.Err() != nil {
+			return errors.New("delivery context canceled")
+		}
+		return nil
+	}
+
+	run, stop := Consumer[int](&fakeConn{fb}, fb, "worker", fb.deliveries, handle)
+	if err := run(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := stop(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	// The delivery received before the cancellation is still handled.
+	if !slices.Equal(handled, []int{1, 2}) {
+		t.Fatalf("expected both deliveries to be handled, got %v", handled)
+	}
+	if want := []string{"cancel worker", "close", "close connection"}; !slices.Equal(fb.calls, want) {
+		t.Fatalf("expected %v, got %v", want, fb.calls)
+	}
+}
+
+func TestConsumerDeliveriesClosedUnexpectedly(t *testing.T) {
+	fb := &fakeBroker{deliveries: make(chan int)}
+	close(fb.deliveries)
+
+	run, _ := Consumer[int](&fakeConn{fb}, fb, "worker", fb.deliveries, func(ctx context.Context, d int) error { return nil })
+	if err := run(context.Background()); err == nil {
+		t.Fatal("expected an error when deliveries close before shutdown")
+	}
+}
@@ -0,0 +1,116 @@
+// Package kafka adapts Kafka consumers to a graceful lifecycle so shutdown neither loses nor duplicates messages
+// needlessly: fetching stops when shutdown begins, the in-flight message is processed and its offset committed, and
+// the consumer leaves its group before the shutdown deadline.
+//
+// The adapters are defined against small interfaces satisfied by github.com/segmentio/kafka-go and
+// github.com/IBM/sarama, so this package depends on neither.
+package kafka
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/bryhen/graceful"
+)
+
+// The subset of *kafka.Reader (kafka-go) used by Reader(), where M is kafka.Message.
+type MessageReader[M any] interface {
+	FetchMessage(ctx context.Context) (M, error)
+	CommitMessages(ctx context.Context, msgs ...M) error
+	Close() error
+}
+
+// Adapts r (usually a *kafka.Reader with a GroupID) to the lifecycle. Pass run to graceful.WithRun() and stop to the
+// shutdown functions.
+//
+// run fetches messages and passes each to handle, committing its offset once handle succeeds. Fetching stops when
+// shutdown begins, but the message being handled receives a context which is not canceled then, so it completes and is
+// committed. If handle fails, its message is not committed and the error is returned, which triggers shutdown.
+//
+// stop waits for run to return, then closes r, which leaves the consumer group. If the shutdown context is done first,
+// r is closed anyway and the context's error is returned.
+func Reader[M any](r MessageReader[M], handle func(ctx context.Context, msg M) error) (run graceful.Func, stop graceful.Func) {
+	l := newLoop(r.Close)
+
+	run = l.run(func(ctx context.Context) error {
+		msgCtx := context.WithoutCancel(ctx)
+		for {
+			msg, err := r.FetchMessage(ctx)
+			if err != nil {
+				if ctx.Err() != nil {
+					return nil
+				}
+				return fmt.Errorf("failed to fetch message: %w", err)
+			}
+
+			if err := handle(msgCtx, msg); err != nil {
+				return err
+			}
+
+			if err := r.CommitMessages(msgCtx, msg); err != nil {
+				return fmt.Errorf("failed to commit message: %w", err)
+			}
+		}
+	})
+
+	return run, l.stop
+}
+
+// The subset of sarama.ConsumerGroup used by ConsumerGroup(), where H is sarama.ConsumerGroupHandler.
+type Group[H any] interface {
+	Consume(ctx context.Context, topics []string, handler H) error
+	Close() error
+}
+
+// Adapts cg (usually a sarama.ConsumerGroup) to the lifecycle. Pass run to graceful.WithRun() and stop to the shutdown
+// functions.
+//
+// run consumes topics with handler, rejoining after each rebalance. When shutdown begins, the session's context is
+// canceled. handler's ConsumeClaim() must then finish the message it is processing, mark it, and return.
+//
+// stop waits for run to return, then closes cg, which commits the marked offsets and leaves the consumer group. If the
+// shutdown context is done first, cg is closed anyway and the context's error is returned.
+func ConsumerGroup[H any](cg Group[H], topics []string, handler H) (run graceful.Func, stop graceful.Func) {
+	l := newLoop(cg.Close)
+
+	run = l.run(func(ctx context.Context) error {
+		for {
+			if err := cg.Consume(ctx, topics, handler); err != nil && ctx.Err() == nil {
+				return fmt.Errorf("failed to consume: %w", err)
+			}
+			if ctx.Err() != nil {
+				return nil
+			}
+		}
+	})
+
+	return run, l.stop
+}
+
+// A consume loop which stop waits on before closing the consumer.
+type loop struct {
+	closeFn func() error
+	done    chan struct{}
+}
+
+func newLoop(closeFn func() error) *loop {
+	return &loop{closeFn: closeFn, done: make(chan struct{})}
+}
+
+func (l *loop) run(consume graceful.Func) graceful.Func {
+	return func(ctx context.Context) error {
+		defer close(l.done)
+		return consume(ctx)
+	}
+}
+
+func (l *loop) stop(ctx context.Context) error {
+	select {
+	case <-l.done:
+	case <-ctx.Done():
+		l.closeFn()
+		return ctx.Err()
+	}
+
+	return l.closeFn()
+}
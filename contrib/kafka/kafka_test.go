@@ -0,0 +1,123 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+)
+
+type fakeReader struct {
+	msgs chan int
+
+	mu        sync.Mutex
+	committed []int
+	closed    bool
+}
+
+func (fr *fakeReader) FetchMessage(ctx context.Context) (int, error) {
+	select {
+	case msg := <-fr.msgs:
+		return msg, nil
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	}
+}
+
+func (fr *fakeReader) CommitMessages(ctx context.Context, msgs ...int) error {
+	fr.mu.Lock()
+	defer fr.mu.Unlock()
+
+	fr.committed = append(fr.committed, msgs...)
+	return nil
+}
+
+func (fr *fakeReader) Close() error {
+	fr.mu.Lock()
+	defer fr.mu.Unlock()
+
+	fr.closed = true
+	return nil
+}
+
+func TestReaderCommitsInFlightMessage(t *testing.T) {
+	fr := &fakeReader{msgs: make(chan int, 2)}
+	fr.msgs <- 1
+	fr.msgs <- 2
+
+	ctx, cancel := context.WithCancel(context.Background())
+	handle := func(msgCtx context.Context, msg int) error {
+		if msg == 2 {
+			// Shutdown begins while the message is being handled.
+			cancel()
+			if msgCtx.Err() != nil {
+				return errors.New("message context canceled")
+			}
+		}
+		return nil
+	}
+
+	run, stop := Reader[int](fr, handle)
+	if err := run(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := stop(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if !slices.Equal(fr.committed, []int{1, 2}) || !fr.closed {
+		t.Fatalf("expected both messages committed and the reader closed, got %v (closed %v)", fr.committed, fr.closed)
+	}
+}
+
+func TestReaderHandleError(t *testing.T) {
+	errBoom := errors.New("boom")
+	fr := &fakeReader{msgs: make(chan int, 1)}
+	fr.msgs <- 1
+
+	run, _ := Reader[int](fr, func(ctx context.Context, msg int) error { return errBoom })
+	if err := run(context.Background()); err != errBoom {
+		t.Fatalf("expected the handler's error, got %v", err)
+	}
+	if len(fr.committed) != 0 {
+		t.Fatalf("expected the failed message not to be committed, got %v", fr.committed)
+	}
+}
+
+type fakeGroup struct {
+	consumed int
+	closed   bool
+}
+
+func (fg *fakeGroup) Consume(ctx context.Context, topics []string, handler string) error {
+	// Each session ends with a rebalance until shutdown begins.
+	fg.consumed++
+	return nil
+}
+
+func (fg *fakeGroup) Close() error {
+	fg.closed = true
+	return nil
+}
+
+func TestConsumerGroupStopWaitsForRun(t *testing.T) {
+	fg := &fakeGroup{}
+	run, stop := ConsumerGroup[string](fg, []string{"orders"}, "handler")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := run(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := stop(context.Background()); err != nil || !fg.closed || fg.consumed != 1 {
+		t.Fatalf("expected a single session and the group closed, got %v (consumed %d, closed %v)", err, fg.consumed, fg.closed)
+	}
+
+	// stop does not wait on a run which never returns past the shutdown deadline.
+	fg = &fakeGroup{}
+	_, stop = ConsumerGroup[string](fg, nil, "handler")
+	if err := stop(ctx); !errors.Is(err, context.Canceled) || !fg.closed {
+		t.Fatalf("expected the context error and the group closed, got %v (closed %v)", err, fg.closed)
+	}
+}
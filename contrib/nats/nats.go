@@ -0,0 +1,101 @@
+// Package nats adapts NATS subscriptions and connections to a graceful lifecycle, draining them on shutdown so
+// messages already delivered to the client are processed rather than dropped.
+//
+// The adapters are defined against small interfaces satisfied by *nats.Subscription and *nats.Conn from
+// github.com/nats-io/nats.go, so this package does not depend on it.
+package nats
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/bryhen/graceful"
+)
+
+// How often draining is checked for completion, since NATS drains asynchronously.
+const pollInterval = 10 * time.Millisecond
+
+// The subset of *nats.Subscription used by Subscription().
+type Sub interface {
+	Drain() error
+	Unsubscribe() error
+	IsValid() bool
+}
+
+// The subset of *nats.Conn used by Connection().
+type Conn interface {
+	Drain() error
+	IsClosed() bool
+	Close()
+}
+
+// Adapts a subscription to the lifecycle. Pass start to the startup functions and stop to the shutdown functions:
+//
+//	start, stop := nats.Subscription(func() (*nats.Subscription, error) {
+//		return nc.Subscribe("orders", handle)
+//	})
+//
+// start calls subscribe.
+//
+// stop drains the subscription: no further messages are received, and it waits for the handler to process those
+// already delivered. If the shutdown context is done first, it unsubscribes, dropping them, and returns the context's
+// error.
+func Subscription[S Sub](subscribe func() (S, error)) (start graceful.Func, stop graceful.Func) {
+	var sub S
+
+	start = func(ctx context.Context) error {
+		var err error
+		if sub, err = subscribe(); err != nil {
+			return fmt.Errorf("failed to subscribe: %w", err)
+		}
+		return nil
+	}
+
+	stop = func(ctx context.Context) error {
+		if err := sub.Drain(); err != nil {
+			return fmt.Errorf("failed to drain subscription: %w", err)
+		}
+
+		if err := poll(ctx, func() bool { return !sub.IsValid() }); err != nil {
+			sub.Unsubscribe()
+			return err
+		}
+		return nil
+	}
+
+	return start, stop
+}
+
+// Returns a shutdown function which drains nc: every subscription is drained, pending publishes are flushed, and the
+// connection is closed. Run it after the shutdown functions of individual subscriptions. If the shutdown context is
+// done first, nc is closed immediately and the context's error is returned.
+func Connection(nc Conn) graceful.Func {
+	return func(ctx context.Context) error {
+		if err := nc.Drain(); err != nil {
+			nc.Close()
+			return fmt.Errorf("failed to drain connection: %w", err)
+		}
+
+		if err := poll(ctx, nc.IsClosed); err != nil {
+			nc.Close()
+			return err
+		}
+		return nil
+	}
+}
+
+// Blocks until done returns true, or returns ctx's error if it is done first.
+func poll(ctx context.Context, done func() bool) error {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for !done() {
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
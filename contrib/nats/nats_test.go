@@ -0,0 +1,77 @@
+package nats
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// Becomes invalid shortly after Drain(), as NATS drains asynchronously. Never does if stuck.
+type fakeSub struct {
+	stuck        bool
+	drained      atomic.Bool
+	unsubscribed bool
+}
+
+func (fs *fakeSub) Drain() error {
+	if !fs.stuck {
+		time.AfterFunc(20*time.Millisecond, func() { fs.drained.Store(true) })
+	}
+	return nil
+}
+
+func (fs *fakeSub) Unsubscribe() error {
+	fs.unsubscribed = true
+	return nil
+}
+
+func (fs *fakeSub) IsValid() bool {
+	return !fs.drained.Load()
+}
+
+func TestSubscriptionDrains(t *testing.T) {
+	fs := &fakeSub{}
+	start, stop := Subscription(func() (*fakeSub, error) { return fs, nil })
+
+	if err := start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if err := stop(context.Background()); err != nil || fs.unsubscribed {
+		t.Fatalf("expected the subscription to drain, got %v (unsubscribed %v)", err, fs.unsubscribed)
+	}
+}
+
+func TestSubscriptionDrainTimeout(t *testing.T) {
+	fs := &fakeSub{stuck: true}
+	start, stop := Subscription(func() (*fakeSub, error) { return fs, nil })
+	if err := start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Millisecond)
+	defer cancel()
+	if err := stop(ctx); !errors.Is(err, context.DeadlineExceeded) || !fs.unsubscribed {
+		t.Fatalf("expected the subscription to be dropped, got %v (unsubscribed %v)", err, fs.unsubscribed)
+	}
+}
+
+type fakeConn struct {
+	closed atomic.Bool
+}
+
+func (fc *fakeConn) Drain() error {
+	time.AfterFunc(20*time.Millisecond, func() { fc.closed.Store(true) })
+	return nil
+}
+
+func (fc *fakeConn) IsClosed() bool { return fc.closed.Load() }
+func (fc *fakeConn) Close()         { fc.closed.Store(true) }
+
+func TestConnectionDrains(t *testing.T) {
+	fc := &fakeConn{}
+	if err := Connection(fc)(context.Background()); err != nil || !fc.IsClosed() {
+		t.Fatalf("expected the connection to drain and close, got %v", err)
+	}
+}
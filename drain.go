@@ -0,0 +1,95 @@
+package graceful
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+)
+
+// Wraps next to track in-flight requests for the default Runner. See Runner.DrainMiddleware().
+func DrainMiddleware(next http.Handler) (handler http.Handler, drain Func) {
+	return defaultRunner.DrainMiddleware(next)
+}
+
+// Wraps next to track in-flight requests. Once shutdown begins (see Context()), new requests are rejected with 503
+// Service Unavailable and "Retry-After: 1" so clients retry another instance. This includes requests arriving during
+// the shutdown delay (see WithShutdownDelay()), which load balancers still route here.
+//
+// drain is a shutdown function which waits for the in-flight requests to complete. If the shutdown context is done
+// first, it returns the context's error along with the number of requests still in flight:
+//
+//	handler, drain := graceful.DrainMiddleware(mux)
+//	run, stop := graceful.HTTPServer(&http.Server{Handler: handler}, ln)
+//	graceful.Start(nil, []graceful.Func{drain, stop}, graceful.WithRun(run))
+//
+// Useful when the server is not shut down by the first shutdown function, or for handlers hijacked from the server.
+func (r *Runner) DrainMiddleware(next http.Handler) (handler http.Handler, drain Func) {
+	d := &drainer{}
+
+	handler = http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		// Count the request before checking for shutdown, so drain cannot miss a request which was admitted.
+		d.enter()
+		defer d.leave()
+
+		if r.Context().Err() != nil {
+			w.Header().Set("Retry-After", "1")
+			w.Header().Set("Connection", "close")
+			http.Error(w, http.StatusText(http.StatusServiceUnavailable), http.StatusServiceUnavailable)
+			return
+		}
+
+		next.ServeHTTP(w, req)
+	})
+
+	return handler, d.wait
+}
+
+// Counts in-flight requests. idle is closed whenever the count returns to zero.
+type drainer struct {
+	mu       sync.Mutex
+	inFlight int
+	idle     chan struct{}
+}
+
+func (d *drainer) enter() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.inFlight++
+	if d.inFlight == 1 {
+		d.idle = make(chan struct{})
+	}
+}
+
+func (d *drainer) leave() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.inFlight--
+	if d.inFlight == 0 {
+		close(d.idle)
+	}
+}
+
+func (d *drainer) wait(ctx context.Context) error {
+	for {
+		d.mu.Lock()
+		if d.inFlight == 0 {
+			d.mu.Unlock()
+			return nil
+		}
+		idle := d.idle
+		d.mu.Unlock()
+
+		select {
+		case <-idle:
+			// Rejected requests may have arrived since, so check again.
+		case <-ctx.Done():
+			d.mu.Lock()
+			n := d.inFlight
+			d.mu.Unlock()
+			return fmt.Errorf("%d request(s) still in flight: %w", n, ctx.Err())
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package graceful
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestDrainMiddleware(t *testing.T) {
+	r := New()
+
+	entered, release := make(chan struct{}), make(chan struct{})
+	handler, drain := r.DrainMiddleware(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		close(entered)
+		<-release
+	}))
+
+	var rejected *httptest.ResponseRecorder
+	check := func(ctx context.Context) error {
+		rejected = httptest.NewRecorder()
+		handler.ServeHTTP(rejected, httptest.NewRequest(http.MethodGet, "/", nil))
+		close(release)
+		return nil
+	}
+
+	h := r.StartAsync(nil, []Func{check, drain})
+	<-h.Ready()
+
+	inFlight := httptest.NewRecorder()
+	served := make(chan struct{})
+	go func() {
+		defer close(served)
+		handler.ServeHTTP(inFlight, httptest.NewRequest(http.MethodGet, "/", nil))
+	}()
+	<-entered
+
+	h.Stop(nil)
+	if er := h.Wait(); len(er.ErrsShutdown) > 0 {
+		t.Fatalf("expected drain to wait for the in-flight request, got %v", er.ErrsShutdown)
+	}
+	<-served
+
+	if inFlight.Code != http.StatusOK {
+		t.Fatalf("expected the in-flight request to be served, got %d", inFlight.Code)
+	}
+	if rejected.Code != http.StatusServiceUnavailable || rejected.Header().Get("Retry-After") != "1" {
+		t.Fatalf("expected a new request to be rejected once shutdown began, got %d", rejected.Code)
+	}
+}
+
+func TestDrainMiddlewareContextDone(t *testing.T) {
+	entered, release := make(chan struct{}), make(chan struct{})
+	handler, drain := New().DrainMiddleware(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		close(entered)
+		<-release
+	}))
+
+	go handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	<-entered
+	defer close(release)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := drain(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected the context error, got %v", err)
+	}
+}
@@ -0,0 +1,48 @@
+package graceful
+
+import (
+	"fmt"
+	"os"
+	"time"
+)
+
+// Applies settings from environment variables, so they can be tuned without a rebuild. With prefix "GRACEFUL":
+//
+//   - GRACEFUL_STARTUP_TIMEOUT: see WithStartupTimeout().
+//   - GRACEFUL_SHUTDOWN_TIMEOUT: see WithShutdownTimeout().
+//   - GRACEFUL_SHUTDOWN_DELAY: see WithShutdownDelay().
+//
+// Values use time.ParseDuration() syntax (ie "30s"). Unset or empty variables are ignored. Invalid values are reported
+// in ExitReason.ErrStartup. Options after this one override it.
+func WithEnvConfig(prefix string) Option {
+	vars := []struct {
+		name string
+		opt  func(time.Duration) Option
+	}{
+		{"STARTUP_TIMEOUT", WithStartupTimeout},
+		{"SHUTDOWN_TIMEOUT", WithShutdownTimeout},
+		{"SHUTDOWN_DELAY", WithShutdownDelay},
+	}
+
+	return func(config *config) error {
+		for _, v := range vars {
+			key := prefix + "_" + v.name
+
+			val := os.Getenv(key)
+			if val == "" {
+				continue
+			}
+
+			d, err := time.ParseDuration(val)
+			if err != nil {
+				return invalidOption("failed to parse %s: %w", key, err)
+			}
+
+			if err := config.applyDefaults(v.opt(d)); err != nil {
+				return fmt.Errorf("invalid %s: %w", key, err)
+			}
+		}
+
+		return nil
+	}
+}
@@ -0,0 +1,34 @@
+package graceful
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestWithEnvConfig(t *testing.T) {
+	t.Setenv("APP_SHUTDOWN_DELAY", "2s")
+	t.Setenv("APP_SHUTDOWN_TIMEOUT", "")
+
+	c := &config{}
+	if err := parseOptions(c, []Option{WithEnvConfig("APP"), WithShutdownTimeout(5 * time.Second)}); err != nil {
+		t.Fatal(err)
+	}
+	if c.shutdownDelay != 2*time.Second || c.shutdownTimeout != 5*time.Second {
+		t.Fatalf("expected a 2s delay and a 5s timeout, got %s and %s", c.shutdownDelay, c.shutdownTimeout)
+	}
+
+	// Later options override the environment.
+	c = &config{}
+	if err := parseOptions(c, []Option{WithEnvConfig("APP"), WithShutdownDelay(time.Second)}); err != nil {
+		t.Fatal(err)
+	}
+	if c.shutdownDelay != time.Second {
+		t.Fatalf("expected the option to override the environment, got %s", c.shutdownDelay)
+	}
+
+	t.Setenv("APP_STARTUP_TIMEOUT", "soon")
+	if err := parseOptions(&config{}, []Option{WithEnvConfig("APP")}); !errors.Is(err, ErrInvalidOption) {
+		t.Fatalf("expected ErrInvalidOption, got %v", err)
+	}
+}
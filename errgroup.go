@@ -0,0 +1,95 @@
+package graceful
+
+import (
+	"context"
+	"sync"
+)
+
+// The subset of *errgroup.Group from golang.org/x/sync/errgroup used by FromErrGroup(), so this package does not depend
+// on it.
+type ErrGroup interface {
+	Wait() error
+}
+
+// Returns a run function which waits for g, so code already structured around errgroup can run inside the run phase:
+//
+//	g, gCtx := errgroup.WithContext(graceful.Context())
+//	g.Go(func() error { return consume(gCtx) })
+//	g.Go(func() error { return serve(gCtx) })
+//	graceful.Start(startupFns, shutdownFns, graceful.WithRun(graceful.FromErrGroup(g)))
+//
+// The group's first error (as returned by Wait()) triggers shutdown and is reported in ExitReason.ErrRuntime, and its
+// goroutines are awaited during shutdown like any run function. Derive the group's context from Context() so its
+// goroutines are told to stop once shutdown begins. Pass the function to Go() instead of WithRun() if the group
+// returning without an error should not trigger shutdown.
+func FromErrGroup(g ErrGroup) Func {
+	return func(ctx context.Context) error {
+		return g.Wait()
+	}
+}
+
+// Same as errgroup.WithContext(), but the goroutines are bound to the run phase of the default Runner. See
+// Runner.ToErrGroup().
+func ToErrGroup(ctx context.Context) (*Group, context.Context) {
+	return defaultRunner.ToErrGroup(ctx)
+}
+
+// Same as errgroup.WithContext(), but the goroutines are bound to the run phase of this Runner, as if each were launched
+// with Go(): the first error triggers shutdown and is reported in ExitReason.ErrRuntime, and the goroutines are awaited
+// during shutdown. The returned context is canceled when a goroutine first returns an error, when Wait() returns, or
+// when shutdown begins (with the cause of Context()).
+//
+// Goroutines launched before startup completes run immediately, but are only tied to the lifecycle once it does.
+func (r *Runner) ToErrGroup(ctx context.Context) (*Group, context.Context) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	return &Group{runner: r, cancel: cancel}, ctx
+}
+
+// A collection of goroutines bound to a Runner's run phase, with the same API as errgroup.Group. Create one with
+// ToErrGroup().
+type Group struct {
+	runner *Runner
+	cancel context.CancelCauseFunc
+	wg     sync.WaitGroup
+
+	errOnce sync.Once
+	err     error
+}
+
+// Calls fn in a new goroutine. The first call to return a non-nil error cancels the group's context and triggers
+// shutdown; its error will be returned by Wait().
+func (g *Group) Go(fn func() error) {
+	g.wg.Add(1)
+
+	done := make(chan struct{})
+	var err error
+	go func() {
+		defer g.wg.Done()
+		defer close(done)
+
+		if err = fn(); err != nil {
+			g.errOnce.Do(func() {
+				g.err = err
+				g.cancel(err)
+			})
+		}
+	}()
+
+	// Ties the goroutine to the lifecycle without delaying it until the run phase, and without leaking it if it is
+	// launched after shutdown has begun.
+	g.runner.Go(func(ctx context.Context) error {
+		lcCtx := g.runner.Context()
+		stop := context.AfterFunc(lcCtx, func() { g.cancel(context.Cause(lcCtx)) })
+		defer stop()
+
+		<-done
+		return err
+	})
+}
+
+// Blocks until every goroutine launched with Go() has returned, then returns the first non-nil error (if any).
+func (g *Group) Wait() error {
+	g.wg.Wait()
+	g.cancel(g.err)
+	return g.err
+}
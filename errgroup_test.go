@@ -0,0 +1,73 @@
+package graceful
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+type waitGroup struct{ err error }
+
+func (wg waitGroup) Wait() error { return wg.err }
+
+func TestFromErrGroup(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	er := New().Start(nil, nil, WithRun(FromErrGroup(waitGroup{err: errBoom})))
+	if !errors.Is(er.ErrRuntime, errBoom) {
+		t.Fatalf("expected the group's error to trigger shutdown, got %v", er.ErrRuntime)
+	}
+}
+
+func TestToErrGroup(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	r := New()
+	var waitErr, gCause error
+	run := func(ctx context.Context) error {
+		g, gCtx := r.ToErrGroup(ctx)
+		g.Go(func() error {
+			<-gCtx.Done()
+			return nil
+		})
+		g.Go(func() error { return errBoom })
+
+		waitErr = g.Wait()
+		gCause = context.Cause(gCtx)
+		<-ctx.Done()
+		return nil
+	}
+
+	er := r.Start(nil, nil, WithRun(run))
+	if !errors.Is(er.ErrRuntime, errBoom) {
+		t.Fatalf("expected the first error to trigger shutdown, got %v", er.ErrRuntime)
+	}
+	if waitErr != errBoom || gCause != errBoom {
+		t.Fatalf("expected Wait() and the group's cause to be the first error, got %v and %v", waitErr, gCause)
+	}
+}
+
+func TestToErrGroupCanceledByShutdown(t *testing.T) {
+	r := New()
+	stopped := make(chan struct{})
+	run := func(ctx context.Context) error {
+		g, gCtx := r.ToErrGroup(context.Background())
+		g.Go(func() error {
+			<-gCtx.Done()
+			close(stopped)
+			return nil
+		})
+		r.Stop()
+		return nil
+	}
+
+	er := r.Start(nil, nil, WithRun(run))
+	if er.HasErrors() {
+		t.Fatalf("unexpected errors: %v", er)
+	}
+	select {
+	case <-stopped:
+	default:
+		t.Fatal("expected the group's goroutines to be awaited after shutdown canceled their context")
+	}
+}
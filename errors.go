@@ -0,0 +1,71 @@
+package graceful
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	// Reported in ExitReason.ErrStartup when the startup timeout elapses, and returned by context.Cause() for the context
+	// passed to startup functions. Also matches context.DeadlineExceeded.
+	ErrStartupTimeout = fmt.Errorf("startup timeout exceeded: %w", context.DeadlineExceeded)
+
+	// Reported in ExitReason.ErrsShutdown when the shutdown timeout elapses, and returned by context.Cause() for the
+	// context passed to shutdown and rollback functions. Also matches context.DeadlineExceeded.
+	ErrShutdownTimeout = fmt.Errorf("shutdown timeout exceeded: %w", context.DeadlineExceeded)
+
+	// Reported in ExitReason.ErrsShutdown when a single shutdown function exceeds WithPerShutdownTimeout(), and returned
+	// by context.Cause() for its context. Also matches context.DeadlineExceeded.
+	ErrShutdownFuncTimeout = fmt.Errorf("shutdown function timeout exceeded: %w", context.DeadlineExceeded)
+
+	// Reported in ExitReason.ErrsShutdown when the signal given to WithKillSignal() aborts shutdown.
+	ErrShutdownKilled = errors.New("shutdown killed by signal")
+
+	// Reported in ExitReason.ErrsShutdown when a second signal aborts shutdown (see WithForceExitOnSecondSignal()). Only
+	// seen in the file given to WithExitReasonFile(), since the process exits.
+	ErrShutdownForced = errors.New("shutdown forced by a second signal")
+)
+
+// Wraps an error returned by a startup function in ExitReason.ErrStartup to identify which function failed.
+type StartupError struct {
+	Step     int    // Position of the function in the startup functions
+	Name     string // As given by Named(), or "" if it was not used
+	Err      error
+	Duration time.Duration // How long the function ran
+}
+
+func (se *StartupError) Error() string {
+	return position("startup", se.Step, se.Name, se.Err) + se.Err.Error()
+}
+
+func (se *StartupError) Unwrap() error {
+	return se.Err
+}
+
+// Wraps each error in ExitReason.ErrsShutdown to identify the shutdown (or rollback) function which produced it.
+type ShutdownError struct {
+	Phase    string // "shutdown" or "rollback"
+	Index    int    // Position of the function in the phase
+	Name     string // As given by Named(), or "" if it was not used
+	Err      error
+	Duration time.Duration // How long the function ran. Zero if it had not returned when the shutdown timeout elapsed.
+}
+
+func (se *ShutdownError) Error() string {
+	return position(se.Phase, se.Index, se.Name, se.Err) + se.Err.Error()
+}
+
+func (se *ShutdownError) Unwrap() error {
+	return se.Err
+}
+
+// Returns the prefix identifying a function in an error message, ie "shutdown[2]: ". The name is included unless err
+// already carries it, which is the case unless err is a timeout reported on the function's behalf.
+func position(phase string, i int, name string, err error) string {
+	if name != "" && FuncName(err) == "" {
+		return fmt.Sprintf("%s[%d] %s: ", phase, i, name)
+	}
+	return fmt.Sprintf("%s[%d]: ", phase, i)
+}
@@ -0,0 +1,99 @@
+package graceful
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// A phase of the lifecycle, as reported to WithErrorReporter().
+type Phase string
+
+const (
+	PhaseStartup  Phase = "startup"
+	PhaseRollback Phase = "rollback"
+	PhaseRuntime  Phase = "runtime"
+	PhaseShutdown Phase = "shutdown"
+)
+
+// report is called with every error as it happens, rather than only once the lifecycle ends: errors returned by
+// startup, rollback, and shutdown functions (as *StartupError and *ShutdownError), startup and shutdown timeouts,
+// registration failures, and runtime errors (from Shutdown(), run functions, and Go()). Lets crash reporting SDKs
+// capture errors before the process exits. report may be called concurrently, so it must be safe for concurrent use.
+//
+// flush, if non-nil, is called once the shutdown functions have returned, just before Start() returns, so buffered
+// reports can be sent. Its context is not canceled; bound it as needed. Its error is logged. If the process exits before
+// shutdown completes (see WithForceExitOnSecondSignal(), WithKillSignal(), and Policy.Exit), flush is also called, but
+// the exit only waits a few seconds for it. Default: none.
+func WithErrorReporter(report func(phase Phase, err error), flush Func) Option {
+	return func(config *config) error {
+		if report == nil {
+			return invalidOption("error reporter must not be nil")
+		}
+		config.errorReporter = report
+		config.errorFlush = flush
+		return nil
+	}
+}
+
+// Safe to call if no error reporter was provided.
+func (c *config) reportErr(phase Phase, err error) {
+	if c.errorReporter != nil && err != nil {
+		c.errorReporter(phase, err)
+	}
+}
+
+func (c *config) flushErrs(ctx context.Context) {
+	if c.errorFlush == nil {
+		return
+	}
+	if err := c.errorFlush(context.WithoutCancel(ctx)); err != nil {
+		c.log(slog.LevelError, "failed to flush error reporter", slog.Any("err", err))
+	}
+}
+
+// Bounds flushErrsBeforeExit(), so a stuck error reporter cannot keep the process from exiting.
+const exitFlushTimeout = 2 * time.Second
+
+// Same as flushErrs(), but for a process about to exit before the lifecycle ends (ie a forced exit): the flush gets at
+// most exitFlushTimeout, after which it is abandoned.
+func (c *config) flushErrsBeforeExit() {
+	if c.errorFlush == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), exitFlushTimeout)
+	defer cancel()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		c.flushErrs(ctx)
+	}()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		c.log(slog.LevelError, "error reporter flush timed out, exiting anyway", slog.Duration("timeout", exitFlushTimeout))
+	}
+}
+
+// Reports runtime errors recorded by this Runner to config until the returned function is called.
+func (r *Runner) reportRuntime(config *config) (stop func()) {
+	if config.errorReporter == nil {
+		return nop
+	}
+
+	r.rtMu.Lock()
+	defer r.rtMu.Unlock()
+
+	r.rtReport = func(err error) {
+		config.reportErr(PhaseRuntime, err)
+	}
+	return func() {
+		r.rtMu.Lock()
+		defer r.rtMu.Unlock()
+
+		r.rtReport = nil
+	}
+}
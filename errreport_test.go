@@ -0,0 +1,108 @@
+package graceful
+
+import (
+	"context"
+	"errors"
+	"os"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"testing"
+)
+
+// Records the errors reported to it.
+type recordingReporter struct {
+	mu      sync.Mutex
+	phases  []Phase
+	errs    []error
+	flushes atomic.Int32
+}
+
+func (rr *recordingReporter) report(phase Phase, err error) {
+	rr.mu.Lock()
+	defer rr.mu.Unlock()
+
+	rr.phases = append(rr.phases, phase)
+	rr.errs = append(rr.errs, err)
+}
+
+func (rr *recordingReporter) flush(ctx context.Context) error {
+	rr.flushes.Add(1)
+	return nil
+}
+
+func TestErrorReporter(t *testing.T) {
+	errBoom := errors.New("boom")
+	rr := &recordingReporter{}
+
+	fail := func(ctx context.Context) error { return errBoom }
+	New().Start([]Func{Named("db", fail)}, nil, WithErrorReporter(rr.report, rr.flush))
+
+	var se *StartupError
+	if len(rr.errs) == 0 || rr.phases[0] != PhaseStartup || !errors.As(rr.errs[0], &se) || se.Name != "db" {
+		t.Fatalf("expected the startup error to be reported, got %v %v", rr.phases, rr.errs)
+	}
+	if rr.flushes.Load() != 1 {
+		t.Fatalf("expected one flush, got %d", rr.flushes.Load())
+	}
+}
+
+func TestErrorReporterRuntime(t *testing.T) {
+	errBoom := errors.New("boom")
+	rr := &recordingReporter{}
+
+	fail := func(ctx context.Context) error { return errBoom }
+	New().Start(nil, nil, WithRun(fail), WithErrorReporter(rr.report, nil))
+
+	if len(rr.errs) != 1 || rr.phases[0] != PhaseRuntime || rr.errs[0] != errBoom {
+		t.Fatalf("expected the runtime error to be reported, got %v %v", rr.phases, rr.errs)
+	}
+}
+
+func TestErrorReporterFlushedBeforePolicyExit(t *testing.T) {
+	codes := stubExit(t)
+	rr := &recordingReporter{}
+
+	// Runs after the stubbed exit, before the lifecycle flushes again as it ends.
+	var flushedBeforeExit int32
+	check := func(ctx context.Context) error {
+		flushedBeforeExit = rr.flushes.Load()
+		return nil
+	}
+
+	New().Start(nil, []Func{check},
+		WithRun(runUntilSignaled),
+		WithSignalPolicy(map[os.Signal]Policy{syscall.SIGTERM: {Exit: true, ExitCode: 3}}),
+		WithErrorReporter(rr.report, rr.flush),
+	)
+
+	if code := <-codes; code != 3 || flushedBeforeExit != 1 {
+		t.Fatalf("expected a flush before exiting with code 3, got %d flushes and code %d", flushedBeforeExit, code)
+	}
+}
+
+func TestErrorReporterFlushedBeforeKill(t *testing.T) {
+	codes := stubExit(t)
+	rr := &recordingReporter{}
+
+	orig := os.Stderr
+	os.Stderr, _ = os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+	t.Cleanup(func() { os.Stderr = orig })
+
+	kill := testSignal("kill")
+	r := New()
+	stuck := func(ctx context.Context) error {
+		r.Signal(kill)
+		<-codes
+		return nil
+	}
+
+	r.Shutdown(nil)
+	r.Start(nil, []Func{stuck}, WithKillSignal(kill), WithErrorReporter(rr.report, rr.flush))
+
+	rr.mu.Lock()
+	defer rr.mu.Unlock()
+	if len(rr.errs) != 1 || rr.errs[0] != ErrShutdownKilled || rr.flushes.Load() != 2 {
+		t.Fatalf("expected the kill to be reported and flushed, got %v and %d flushes", rr.errs, rr.flushes.Load())
+	}
+}
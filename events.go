@@ -0,0 +1,123 @@
+package graceful
+
+import (
+	"os"
+	"slices"
+	"time"
+)
+
+// Identifies a lifecycle transition.
+type EventType int
+
+const (
+	EventStartupBegan EventType = iota + 1
+	EventStartupFuncCompleted
+	EventReady
+	EventSignalReceived
+	EventShutdownBegan
+	EventShutdownFuncCompleted
+	EventExited
+	EventReloadBegan
+	EventReloadCompleted
+	EventShutdownInhibited
+	EventMaintenanceBegan
+	EventMaintenanceEnded
+	EventChildExited
+)
+
+func (et EventType) String() string {
+	switch et {
+	case EventStartupBegan:
+		return "StartupBegan"
+	case EventStartupFuncCompleted:
+		return "StartupFuncCompleted"
+	case EventReady:
+		return "Ready"
+	case EventSignalReceived:
+		return "SignalReceived"
+	case EventShutdownBegan:
+		return "ShutdownBegan"
+	case EventShutdownFuncCompleted:
+		return "ShutdownFuncCompleted"
+	case EventExited:
+		return "Exited"
+	case EventReloadBegan:
+		return "ReloadBegan"
+	case EventReloadCompleted:
+		return "ReloadCompleted"
+	case EventShutdownInhibited:
+		return "ShutdownInhibited"
+	case EventMaintenanceBegan:
+		return "MaintenanceBegan"
+	case EventMaintenanceEnded:
+		return "MaintenanceEnded"
+	case EventChildExited:
+		return "ChildExited"
+	default:
+		return "Unknown"
+	}
+}
+
+// Describes a lifecycle transition. Only the fields relevant to Type are set.
+type Event struct {
+	Type EventType
+	Time time.Time
+
+	// Set for EventStartupFuncCompleted and EventShutdownFuncCompleted. Phase is "startup", "shutdown", or "rollback".
+	// Duration and Err are also set for EventReloadCompleted, EventMaintenanceBegan, and EventMaintenanceEnded. Name and
+	// Duration are set for EventShutdownInhibited: the reason given to Inhibit() and how long it has been held.
+	Phase    string
+	Index    int
+	Name     string
+	Duration time.Duration
+	Err      error
+
+	// Set for EventSignalReceived, EventReloadBegan, EventReloadCompleted, EventMaintenanceBegan, and
+	// EventMaintenanceEnded.
+	Signal os.Signal
+
+	// Set for EventExited and EventChildExited. Name is also set for EventChildExited: the name given to Spawn().
+	ExitReason *ExitReason
+}
+
+// Registers ch to receive the default Runner's lifecycle events.
+//
+// Events are sent without blocking, so they are dropped if ch is not ready to receive. Use a buffered channel.
+func Subscribe(ch chan<- Event) {
+	defaultRunner.Subscribe(ch)
+}
+
+// Stops sending the default Runner's lifecycle events to ch.
+func Unsubscribe(ch chan<- Event) {
+	defaultRunner.Unsubscribe(ch)
+}
+
+// Same as the package-level Subscribe(), but scoped to this Runner.
+func (r *Runner) Subscribe(ch chan<- Event) {
+	r.subsMu.Lock()
+	defer r.subsMu.Unlock()
+
+	r.subs = append(r.subs, ch)
+}
+
+// Same as the package-level Unsubscribe(), but scoped to this Runner.
+func (r *Runner) Unsubscribe(ch chan<- Event) {
+	r.subsMu.Lock()
+	defer r.subsMu.Unlock()
+
+	r.subs = slices.DeleteFunc(r.subs, func(c chan<- Event) bool { return c == ch })
+}
+
+func (r *Runner) emit(e Event) {
+	e.Time = time.Now()
+
+	r.subsMu.Lock()
+	defer r.subsMu.Unlock()
+
+	for _, ch := range r.subs {
+		select {
+		case ch <- e:
+		default:
+		}
+	}
+}
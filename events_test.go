@@ -0,0 +1,65 @@
+package graceful
+
+import (
+	"context"
+	"slices"
+	"syscall"
+	"testing"
+)
+
+func TestEvents(t *testing.T) {
+	r := New()
+	events := make(chan Event, 64)
+	r.Subscribe(events)
+
+	ok := func(ctx context.Context) error {
+		return nil
+	}
+	run := func(ctx context.Context) error {
+		if err := signalSelf(syscall.SIGTERM); err != nil {
+			return err
+		}
+		<-ctx.Done()
+		return nil
+	}
+
+	r.Start([]Func{Named("db", ok)}, []Func{ok}, WithRun(run))
+	r.Unsubscribe(events)
+	close(events)
+
+	var got []EventType
+	for e := range events {
+		got = append(got, e.Type)
+		switch e.Type {
+		case EventStartupFuncCompleted:
+			if e.Phase != "startup" || e.Name != "db" {
+				t.Errorf("expected the startup function's phase and name, got %q and %q", e.Phase, e.Name)
+			}
+		case EventSignalReceived:
+			if e.Signal != syscall.SIGTERM {
+				t.Errorf("expected SIGTERM, got %v", e.Signal)
+			}
+		case EventExited:
+			if e.ExitReason == nil || e.ExitReason.OsSignal != syscall.SIGTERM {
+				t.Errorf("expected EventExited to carry the exit reason, got %v", e.ExitReason)
+			}
+		}
+	}
+
+	want := []EventType{EventStartupBegan, EventStartupFuncCompleted, EventReady, EventSignalReceived, EventShutdownBegan,
+		EventShutdownFuncCompleted, EventExited}
+	if !slices.Equal(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+}
+
+func TestEventsDroppedWhenNotReady(t *testing.T) {
+	r := New()
+	events := make(chan Event)
+	r.Subscribe(events)
+	defer r.Unsubscribe(events)
+
+	// Nobody receives from events, so emitting must not block the lifecycle.
+	r.Shutdown(nil)
+	r.Start(nil, nil)
+}
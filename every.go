@@ -0,0 +1,113 @@
+package graceful
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Configures Every().
+type EveryOption func(*everyConfig)
+
+type everyConfig struct {
+	skipOverlap bool
+	immediate   bool
+}
+
+// A tick is skipped if the previous run of the task has not returned. Default: runs may overlap.
+func SkipOverlapping() EveryOption {
+	return func(ec *everyConfig) {
+		ec.skipOverlap = true
+	}
+}
+
+// The task also runs as soon as the run phase begins, rather than only after the first interval. Default: off.
+func Immediately() EveryOption {
+	return func(ec *everyConfig) {
+		ec.immediate = true
+	}
+}
+
+// Returns a run function (see WithRun()) which runs fn every d until shutdown begins, then waits for the in-flight runs
+// to return. fn receives a context which is not canceled when shutdown begins, so a run in progress can complete within
+// the shutdown timeout. It is canceled with ErrShutdownTimeout once the timeout elapses.
+//
+// If fn returns an error, no further runs are started and the error is returned, which triggers shutdown. Wrap fn with
+// BestEffort() to keep running after failures. If d is not positive, the run function returns an error immediately.
+func Every(d time.Duration, fn Func, opts ...EveryOption) Func {
+	ec := &everyConfig{}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(ec)
+		}
+	}
+
+	return func(ctx context.Context) error {
+		if d <= 0 {
+			return fmt.Errorf("interval must be positive, got %s", d)
+		}
+
+		taskCtx, taskCancel := untilShutdownDeadline(ctx)
+		defer taskCancel()
+		// Measured by the lifecycle's clock (see WithClock()), which has no tickers, so the next tick is armed as soon as
+		// one fires.
+		next := afterFrom(ctx, d)
+
+		var (
+			wg      sync.WaitGroup
+			mu      sync.Mutex
+			running bool
+			errCh   = make(chan error, 1)
+		)
+
+		tick := func() {
+			mu.Lock()
+			if ec.skipOverlap && running {
+				mu.Unlock()
+				return
+			}
+			running = true
+			mu.Unlock()
+
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+
+				err := fn(taskCtx)
+
+				mu.Lock()
+				running = false
+				mu.Unlock()
+
+				if err != nil && !isBestEffort(err) {
+					select {
+					case errCh <- err:
+					default:
+					}
+				}
+			}()
+		}
+
+		if ec.immediate {
+			tick()
+		}
+
+		var err error
+	loop:
+		for {
+			select {
+			case <-next:
+				next = afterFrom(ctx, d)
+				tick()
+			case err = <-errCh:
+				break loop
+			case <-ctx.Done():
+				break loop
+			}
+		}
+
+		wg.Wait()
+		return err
+	}
+}
@@ -0,0 +1,95 @@
+package graceful
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestEveryCancelsTasksAtShutdownDeadline(t *testing.T) {
+	r := New()
+
+	causes := make(chan error, 1)
+	task := func(ctx context.Context) error {
+		r.Stop()
+		<-ctx.Done()
+		causes <- context.Cause(ctx)
+		return nil
+	}
+
+	er := r.Start(nil, nil, WithRun(Every(time.Hour, task, Immediately())), WithShutdownTimeout(50*time.Millisecond))
+
+	select {
+	case cause := <-causes:
+		if !errors.Is(cause, ErrShutdownTimeout) {
+			t.Fatalf("expected the task to be canceled with ErrShutdownTimeout, got %v", cause)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the task to be canceled at the shutdown deadline")
+	}
+	if !errors.Is(errors.Join(er.ErrsShutdown...), ErrShutdownTimeout) {
+		t.Fatalf("expected the shutdown timeout to be reported, got %v", er.ErrsShutdown)
+	}
+}
+
+func TestEveryLetsTasksFinishDuringShutdown(t *testing.T) {
+	r := New()
+
+	finished := false
+	task := func(ctx context.Context) error {
+		r.Stop()
+		time.Sleep(20 * time.Millisecond)
+		finished = ctx.Err() == nil
+		return nil
+	}
+
+	er := r.Start(nil, nil, WithRun(Every(time.Hour, task, Immediately())), WithShutdownTimeout(5*time.Second))
+	if !finished || !er.Clean() {
+		t.Fatalf("expected the task to finish before the deadline, got %v", er)
+	}
+}
+
+func TestEveryRejectsNonPositiveInterval(t *testing.T) {
+	task := func(ctx context.Context) error {
+		return nil
+	}
+
+	if err := Every(0, task)(context.Background()); err == nil {
+		t.Fatal("expected an error for a zero interval")
+	}
+}
+
+// Fires a pending After() whenever a time is sent on ticks.
+type tickClock struct {
+	ticks chan time.Time
+}
+
+func (tc *tickClock) Now() time.Time                         { return time.Now() }
+func (tc *tickClock) After(d time.Duration) <-chan time.Time { return tc.ticks }
+
+func TestEveryUsesClock(t *testing.T) {
+	r := New()
+	clock := &tickClock{ticks: make(chan time.Time)}
+
+	var runs atomic.Int32
+	task := func(ctx context.Context) error {
+		if runs.Add(1) == 2 {
+			r.Stop()
+		}
+		return nil
+	}
+
+	// An hour elapses each time the clock ticks.
+	go func() {
+		for range 2 {
+			clock.ticks <- time.Now()
+		}
+	}()
+
+	er := r.Start(nil, nil, WithRun(Every(time.Hour, task)), WithClock(clock))
+	if runs.Load() != 2 || !er.Clean() {
+		t.Fatalf("expected a run per tick of the clock, got %d runs (%v)", runs.Load(), er)
+	}
+}
@@ -0,0 +1,60 @@
+package graceful
+
+import (
+	"context"
+	"fmt"
+)
+
+// Derives a process exit code from the struct following common conventions:
+//
+//   - 128 + the signal number if the program exited due to an OS signal which was not preceded by a startup error.
+//   - 1 if any error was recorded (startup, runtime, context, or shutdown).
+//   - 0 otherwise.
+func (er *ExitReason) ExitCode() int {
+	if er.ErrStartup == nil && er.OsSignal != nil {
+		if n, ok := signalNumber(er.OsSignal); ok {
+			return 128 + n
+		}
+		return 1
+	}
+
+	if er.HasErrors() {
+		return 1
+	}
+
+	return 0
+}
+
+// Reports whether any error was recorded: startup, runtime, context, or shutdown. An OS signal is not an error.
+func (er *ExitReason) HasErrors() bool {
+	return er.ErrStartup != nil || er.ErrRuntime != nil || len(er.ErrsRuntime) > 0 || er.ErrContext != nil ||
+		len(er.ErrsShutdown) > 0
+}
+
+// Reports whether the program exited cleanly: due to an OS signal or Shutdown(nil), with no errors during startup,
+// runtime, or shutdown.
+func (er *ExitReason) Clean() bool {
+	return !er.HasErrors()
+}
+
+// Same as Start(), but exits the process with ExitReason.ExitCode() once it returns.
+//
+// If WithExitWriter() is provided, the ExitReason is written to it as indented JSON before exiting.
+func Run(startupFns []Func, shutdownFns []Func, opts ...Option) {
+	defaultRunner.Run(startupFns, shutdownFns, opts...)
+}
+
+// Same as the package-level Run(), but scoped to this Runner.
+func (r *Runner) Run(startupFns []Func, shutdownFns []Func, opts ...Option) {
+	er, config := r.start(context.Background(), startupFns, shutdownFns, opts)
+
+	if config.exitWriter != nil {
+		if s, err := er.MarshalIndentStrErr("", "\t"); err != nil {
+			fmt.Fprintf(config.exitWriter, "failed to marshal exit reason: %v\n", err)
+		} else {
+			fmt.Fprintln(config.exitWriter, s)
+		}
+	}
+
+	osExit(er.ExitCode())
+}
@@ -0,0 +1,93 @@
+package graceful
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"strings"
+	"syscall"
+	"testing"
+)
+
+func TestExitCode(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	sigCode := 1
+	if n, ok := signalNumber(syscall.SIGTERM); ok {
+		sigCode = 128 + n
+	}
+
+	tests := []struct {
+		name string
+		er   *ExitReason
+		want int
+	}{
+		{"clean", &ExitReason{}, 0},
+		{"signal", &ExitReason{OsSignal: syscall.SIGTERM}, sigCode},
+		{"startup error before signal", &ExitReason{ErrStartup: errBoom, OsSignal: syscall.SIGTERM}, 1},
+		{"runtime error", &ExitReason{ErrRuntime: errBoom}, 1},
+		{"shutdown error", &ExitReason{ErrsShutdown: []error{errBoom}}, 1},
+		{"unnumbered signal", &ExitReason{OsSignal: namedSignal("note")}, 1},
+		{"warnings only", &ExitReason{WarningsShutdown: []error{errBoom}}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.er.ExitCode(); got != tt.want {
+				t.Fatalf("expected %d, got %d", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestClean(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	tests := []struct {
+		name string
+		er   *ExitReason
+		want bool
+	}{
+		{"nothing recorded", &ExitReason{}, true},
+		{"signal", &ExitReason{OsSignal: syscall.SIGTERM}, true},
+		{"startup error", &ExitReason{ErrStartup: errBoom}, false},
+		{"runtime errors only", &ExitReason{ErrsRuntime: []error{errBoom}}, false},
+		{"context error", &ExitReason{ErrContext: errBoom}, false},
+		{"shutdown error", &ExitReason{OsSignal: syscall.SIGTERM, ErrsShutdown: []error{errBoom}}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.er.Clean() != tt.want || tt.er.HasErrors() == tt.want {
+				t.Fatalf("expected Clean() to be %v, got Clean() %v and HasErrors() %v", tt.want, tt.er.Clean(), tt.er.HasErrors())
+			}
+		})
+	}
+}
+
+// Replaces os.Exit for the duration of the test and returns the codes passed to it.
+func stubExit(t *testing.T) <-chan int {
+	t.Helper()
+
+	codes := make(chan int, 8)
+	osExit = func(code int) { codes <- code }
+	t.Cleanup(func() { osExit = os.Exit })
+	return codes
+}
+
+func TestRun(t *testing.T) {
+	codes := stubExit(t)
+
+	r := New()
+	r.Shutdown(errors.New("boom"))
+
+	var w bytes.Buffer
+	r.Run(nil, nil, WithExitWriter(&w))
+
+	if code := <-codes; code != 1 {
+		t.Fatalf("expected exit code 1, got %d", code)
+	}
+	if !strings.Contains(w.String(), `"errRuntime": "boom"`) {
+		t.Fatalf("expected the exit reason to be written, got %q", w.String())
+	}
+}
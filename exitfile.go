@@ -0,0 +1,76 @@
+package graceful
+
+import (
+	"encoding/json"
+	"log/slog"
+	"os"
+	"path/filepath"
+)
+
+// When the lifecycle ends, the ExitReason is written to path as JSON, replacing the file atomically so a crash while
+// writing cannot leave it truncated. It is also written with the partial ExitReason when the process exits before
+// shutdown completes: when WithKillSignal() or WithForceExitOnSecondSignal() aborts it, or a Policy exits immediately.
+// Load it at the next startup with LoadLastExitReason() to report why the previous process exited, even if its logs
+// were lost. Failures to write are logged. Default: nothing is written.
+func WithExitReasonFile(path string) Option {
+	return func(config *config) error {
+		if path == "" {
+			return invalidOption("exit reason file path must not be empty")
+		}
+		config.exitReasonFile = path
+		return nil
+	}
+}
+
+// Reads the ExitReason written by WithExitReasonFile(). The error matches os.ErrNotExist if no ExitReason was written,
+// ie on the first run.
+func LoadLastExitReason(path string) (*ExitReason, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	er := &ExitReason{}
+	if err := json.Unmarshal(b, er); err != nil {
+		return nil, err
+	}
+	return er, nil
+}
+
+// Writes er to the file given to WithExitReasonFile(), if any.
+func writeExitReasonFile(config *config, er *ExitReason) {
+	if config.exitReasonFile == "" {
+		return
+	}
+
+	b, err := json.Marshal(er)
+	if err == nil {
+		err = writeFileAtomic(config.exitReasonFile, b)
+	}
+	if err != nil {
+		config.log(slog.LevelError, "failed to write exit reason file", slog.String("path", config.exitReasonFile),
+			slog.Any("err", err))
+	}
+}
+
+// Writes b to a temporary file in the same directory as path, then renames it over path.
+func writeFileAtomic(path string, b []byte) error {
+	f, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	if _, err := f.Write(b); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
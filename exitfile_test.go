@@ -0,0 +1,36 @@
+package graceful
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestExitReasonFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "exit.json")
+	errBoom := errors.New("boom")
+
+	if _, err := LoadLastExitReason(path); !errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("expected fs.ErrNotExist before the first run, got %v", err)
+	}
+
+	fail := func(ctx context.Context) error { return errBoom }
+	New().Start([]Func{Named("db", fail)}, nil, WithExitReasonFile(path))
+
+	er, err := LoadLastExitReason(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if er.ErrStartup == nil || er.ErrStartup.Error() != "startup[0]: db: boom" {
+		t.Fatalf("expected the startup error to be restored, got %v", er.ErrStartup)
+	}
+
+	// Only the complete file is ever visible.
+	entries, err := os.ReadDir(filepath.Dir(path))
+	if err != nil || len(entries) != 1 {
+		t.Fatalf("expected no temporary files to remain, got %v (%v)", entries, err)
+	}
+}
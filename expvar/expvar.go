@@ -0,0 +1,66 @@
+// Package expvar publishes a graceful.Runner's lifecycle via the standard expvar package, for fleets which already
+// scrape /debug/vars.
+//
+// It is separate from package graceful because importing expvar registers its handler on http.DefaultServeMux, which
+// must not happen to applications that do not ask for it.
+package expvar
+
+import (
+	stdexpvar "expvar"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/bryhen/graceful"
+)
+
+// The name the lifecycle is published under.
+const Name = "graceful"
+
+var (
+	publishOnce sync.Once
+	published   atomic.Pointer[graceful.Runner]
+)
+
+// Publishes the lifecycle of the Runner the option is given to under Name, as a JSON object with:
+//
+//   - state: the current graceful.State.
+//   - uptime and uptimeSeconds: how long the Runner has been running since startup completed, while it is running.
+//   - startupDuration: how long the last startup took.
+//   - lastExitReason: the ExitReason of the previous lifecycle in this process (ie before a restart by
+//     graceful.Supervise()) or, if graceful.WithExitReasonFile() is also given, the one persisted by the previous
+//     process.
+//
+// The values are read from Runner.Stats() whenever the variable is requested. If several Runners are started with the
+// option, the latest one is published.
+func WithExpvar() graceful.Option {
+	return graceful.WithRunner(func(r *graceful.Runner) graceful.Option {
+		publishOnce.Do(func() {
+			stdexpvar.Publish(Name, stdexpvar.Func(value))
+		})
+		published.Store(r)
+		return nil
+	})
+}
+
+func value() any {
+	r := published.Load()
+	if r == nil {
+		return nil
+	}
+	stats := r.Stats()
+
+	m := map[string]any{
+		"state":           stats.State.String(),
+		"startupDuration": stats.StartupDuration.String(),
+	}
+	if !stats.ReadyAt.IsZero() {
+		uptime := time.Since(stats.ReadyAt)
+		m["uptime"] = uptime.String()
+		m["uptimeSeconds"] = uptime.Seconds()
+	}
+	if stats.LastExitReason != nil {
+		m["lastExitReason"] = stats.LastExitReason
+	}
+	return m
+}
@@ -0,0 +1,52 @@
+package expvar
+
+import (
+	"context"
+	"encoding/json"
+	stdexpvar "expvar"
+	"testing"
+
+	"github.com/bryhen/graceful"
+)
+
+func get(t *testing.T) map[string]any {
+	t.Helper()
+
+	v := stdexpvar.Get(Name)
+	if v == nil {
+		t.Fatalf("expected %q to be published", Name)
+	}
+
+	var m map[string]any
+	if err := json.Unmarshal([]byte(v.String()), &m); err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+func TestWithExpvar(t *testing.T) {
+	r := graceful.New()
+
+	var running map[string]any
+	run := func(ctx context.Context) error {
+		running = get(t)
+		return nil
+	}
+
+	// Started twice, since publishing the same name again must not panic.
+	for range 2 {
+		r.Start(nil, nil, graceful.WithRun(run), WithExpvar())
+	}
+
+	if running["state"] != "Running" || running["uptime"] == nil {
+		t.Fatalf("expected a running lifecycle with an uptime, got %v", running)
+	}
+	if running["lastExitReason"] == nil {
+		t.Fatalf("expected the first lifecycle's exit reason while the second runs, got %v", running)
+	}
+
+	stopped := get(t)
+	if stopped["state"] != "Stopped" || stopped["uptime"] != nil {
+		t.Fatalf("expected a stopped lifecycle without an uptime, got %v", stopped)
+	}
+}
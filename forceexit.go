@@ -0,0 +1,111 @@
+package graceful
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+)
+
+// Exits the process if a shutdown signal is received before the returned function is called, writing what is known of
+// er so far to the file given to WithExitReasonFile(). Does nothing unless WithForceExitOnSecondSignal() was provided.
+// Called once shutdown has begun, so er's trigger is already set.
+func (r *Runner) watchForceExit(config *config, er *ExitReason) (stop func()) {
+	if !config.forceExit {
+		return nop
+	}
+
+	partial := partialExitReason(er)
+
+	osSig := make(chan os.Signal, 1)
+	r.notify(osSig, config.signals...)
+
+	done := make(chan struct{})
+	go func() {
+		select {
+		case sig := <-osSig:
+			config.log(slog.LevelWarn, "signal received during shutdown, forcing exit",
+				slog.String("signal", sig.String()), slog.Int("code", config.forceExitCode))
+
+			partial.abort(config, ErrShutdownForced)
+			writeExitReasonFile(config, partial)
+			config.reportErr(PhaseShutdown, ErrShutdownForced)
+			config.flushErrsBeforeExit()
+			osExit(config.forceExitCode)
+		case <-done:
+		}
+	}()
+
+	return func() {
+		r.stopNotify(osSig)
+		close(done)
+	}
+}
+
+// Exits the process if the signal given to WithKillSignal() is received before the returned function is called,
+// writing what is known of er so far to os.Stderr. Called once shutdown has begun, so er's trigger is already set.
+func (r *Runner) watchKill(config *config, er *ExitReason) (stop func()) {
+	if config.killSignal == nil {
+		return nop
+	}
+
+	partial := partialExitReason(er)
+
+	killSig := make(chan os.Signal, 1)
+	r.notify(killSig, config.killSignal)
+
+	done := make(chan struct{})
+	go func() {
+		select {
+		case sig := <-killSig:
+			config.log(slog.LevelError, "kill signal received during shutdown, exiting",
+				slog.String("signal", sig.String()))
+
+			partial.abort(config, ErrShutdownKilled)
+			partial.GoroutineDump = goroutineDump()
+
+			fmt.Fprintln(os.Stderr, partial.MarshalIndentStr("", "\t"))
+			writeExitReasonFile(config, partial)
+
+			config.reportErr(PhaseShutdown, ErrShutdownKilled)
+			config.flushErrsBeforeExit()
+
+			code := 1
+			if n, ok := signalNumber(sig); ok {
+				code = 128 + n
+			}
+			osExit(code)
+		case <-done:
+		}
+	}()
+
+	return func() {
+		r.stopNotify(killSig)
+		close(done)
+	}
+}
+
+// Copies the fields of er which are final once shutdown has begun, since the lifecycle goroutine keeps writing to er.
+func partialExitReason(er *ExitReason) *ExitReason {
+	return &ExitReason{
+		ErrStartup:      er.ErrStartup,
+		OsSignal:        er.OsSignal,
+		ErrRuntime:      er.ErrRuntime,
+		ErrsRuntime:     er.ErrsRuntime,
+		ErrContext:      er.ErrContext,
+		Reason:          er.Reason,
+		WarningsStartup: er.WarningsStartup,
+	}
+}
+
+// Records the steps which returned so far and the shutdown functions which failed, followed by err, for a process which
+// exits before shutdown completes.
+func (er *ExitReason) abort(config *config, err error) {
+	er.Timing.Steps = config.steps.snapshot()
+	for _, st := range er.Timing.Steps {
+		if st.Phase != "startup" && st.Err != nil {
+			er.ErrsShutdown = append(er.ErrsShutdown,
+				&ShutdownError{Phase: st.Phase, Index: st.Index, Name: st.Name, Err: st.Err, Duration: st.Duration})
+		}
+	}
+	er.ErrsShutdown = append(er.ErrsShutdown, err)
+}
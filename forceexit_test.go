@@ -0,0 +1,131 @@
+package graceful
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestForceExitOnSecondSignal(t *testing.T) {
+	codes := stubExit(t)
+	path := filepath.Join(t.TempDir(), "exit.json")
+
+	run := func(ctx context.Context) error {
+		if err := signalSelf(syscall.SIGTERM); err != nil {
+			return err
+		}
+		<-ctx.Done()
+		return nil
+	}
+	stuck := func(ctx context.Context) error {
+		if err := signalSelf(syscall.SIGTERM); err != nil {
+			return err
+		}
+		select {
+		case code := <-codes:
+			if code != 3 {
+				t.Errorf("expected exit code 3, got %d", code)
+			}
+			// Read before the lifecycle, which keeps running after the stubbed exit, replaces it.
+			er, err := LoadLastExitReason(path)
+			if err != nil || er.OsSignal == nil || !slices.ContainsFunc(er.ErrsShutdown, func(err error) bool {
+				return errors.Is(err, ErrShutdownForced)
+			}) {
+				t.Errorf("expected the partial exit reason of the forced exit, got %v (%v)", er, err)
+			}
+		case <-time.After(5 * time.Second):
+			t.Error("expected the second signal to force an exit")
+		}
+		return nil
+	}
+
+	New().Start(nil, []Func{stuck}, WithRun(run), WithForceExitOnSecondSignal(3), WithExitReasonFile(path))
+}
+
+func TestSecondSignalIgnoredByDefault(t *testing.T) {
+	codes := stubExit(t)
+
+	run := func(ctx context.Context) error {
+		if err := signalSelf(syscall.SIGTERM); err != nil {
+			return err
+		}
+		<-ctx.Done()
+		return nil
+	}
+	stop := func(ctx context.Context) error {
+		if err := signalSelf(syscall.SIGTERM); err != nil {
+			return err
+		}
+		time.Sleep(50 * time.Millisecond)
+		return nil
+	}
+
+	New().Start(nil, []Func{stop}, WithRun(run))
+	select {
+	case code := <-codes:
+		t.Fatalf("expected no forced exit, got code %d", code)
+	default:
+	}
+}
+
+func TestKillSignal(t *testing.T) {
+	codes := stubExit(t)
+
+	// The partial ExitReason is written to os.Stderr.
+	stderr, err := os.CreateTemp(t.TempDir(), "stderr")
+	if err != nil {
+		t.Fatal(err)
+	}
+	orig := os.Stderr
+	os.Stderr = stderr
+	t.Cleanup(func() { os.Stderr = orig })
+
+	kill := testSignal("kill")
+	r := New()
+	stuck := func(ctx context.Context) error {
+		r.Signal(kill)
+		select {
+		case code := <-codes:
+			if code != 1 {
+				t.Errorf("expected exit code 1 for a signal without a number, got %d", code)
+			}
+		case <-time.After(5 * time.Second):
+			t.Error("expected the kill signal to exit")
+		}
+		return nil
+	}
+
+	r.Shutdown(nil)
+	r.Start(nil, []Func{stuck}, WithKillSignal(kill))
+
+	os.Stderr = orig
+	out, err := os.ReadFile(stderr.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(out), ErrShutdownKilled.Error()) {
+		t.Fatalf("expected the partial exit reason on stderr, got %q", out)
+	}
+}
+
+func TestKillSignalIgnoredAfterShutdown(t *testing.T) {
+	codes := stubExit(t)
+
+	kill := testSignal("kill")
+	r := New()
+	r.Shutdown(nil)
+	r.Start(nil, nil, WithKillSignal(kill))
+	r.Signal(kill)
+
+	select {
+	case code := <-codes:
+		t.Fatalf("expected no exit once shutdown completed, got code %d", code)
+	case <-time.After(20 * time.Millisecond):
+	}
+}
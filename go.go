@@ -0,0 +1,78 @@
+package graceful
+
+import "context"
+
+// Launches fn in a goroutine bound to the run phase of the default Runner.
+//
+// The context passed to fn is canceled when shutdown begins and the goroutine is waited on during shutdown (subject to
+// the shutdown timeout). If fn returns a non-nil error before shutdown begins, shutdown is triggered and the error is
+// reported in ExitReason.ErrRuntime.
+//
+// Goroutines requested before startup completes are launched once it does, and are discarded if startup fails.
+// Goroutines requested after shutdown has begun are not launched.
+func Go(fn Func) {
+	defaultRunner.Go(fn)
+}
+
+// Same as the package-level Go(), but scoped to this Runner.
+func (r *Runner) Go(fn Func) {
+	r.goMu.Lock()
+	defer r.goMu.Unlock()
+
+	if r.goCtx == nil {
+		r.goQueue = append(r.goQueue, fn)
+		return
+	}
+
+	r.launchGo(fn)
+}
+
+// Must be called with goMu held.
+func (r *Runner) launchGo(fn Func) {
+	ctx := r.goCtx
+	if ctx.Err() != nil {
+		return
+	}
+
+	r.goWg.Add(1)
+	go func() {
+		defer r.goWg.Done()
+
+		if err := fn(ctx); err != nil {
+			if ctx.Err() == nil {
+				r.Shutdown(err)
+			} else {
+				r.recordRuntime(err)
+			}
+		}
+	}()
+}
+
+// Discards any goroutines which were never launched once the lifecycle ends.
+func (r *Runner) resetGo() {
+	r.goMu.Lock()
+	defer r.goMu.Unlock()
+
+	r.goCtx = nil
+	r.goQueue = nil
+}
+
+// Launches the queued goroutines and any subsequently requested ones under ctx.
+func (r *Runner) beginGo(ctx context.Context) {
+	r.goMu.Lock()
+	defer r.goMu.Unlock()
+
+	r.goCtx = ctx
+	for _, fn := range r.goQueue {
+		r.launchGo(fn)
+	}
+	r.goQueue = nil
+}
+
+// Cancels the goroutines. Holding goMu guarantees no goroutine is launched after cancellation.
+func (r *Runner) endGo(cancel context.CancelFunc) {
+	r.goMu.Lock()
+	defer r.goMu.Unlock()
+
+	cancel()
+}
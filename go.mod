@@ -1,3 +1,8 @@
 module github.com/bryhen/graceful
 
 go 1.22.5
+
+require (
+	go.opentelemetry.io/otel v1.28.0
+	go.opentelemetry.io/otel/trace v1.28.0
+)
@@ -0,0 +1,82 @@
+package graceful
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestGoQueuedUntilStartup(t *testing.T) {
+	r := New()
+
+	started := make(chan struct{})
+	r.Go(func(ctx context.Context) error {
+		close(started)
+		<-ctx.Done()
+		return nil
+	})
+
+	run := func(ctx context.Context) error {
+		<-started
+		return nil
+	}
+
+	if er := r.Start(nil, nil, WithRun(run)); er.ErrRuntime != nil || len(er.ErrsShutdown) != 0 {
+		t.Fatalf("expected a clean exit, got %+v", er)
+	}
+}
+
+func TestGoErrorTriggersShutdown(t *testing.T) {
+	errBoom := errors.New("boom")
+	r := New()
+
+	run := func(ctx context.Context) error {
+		r.Go(func(ctx context.Context) error {
+			return errBoom
+		})
+		<-ctx.Done()
+		return nil
+	}
+
+	er := r.Start(nil, nil, WithRun(run))
+	if !errors.Is(er.ErrRuntime, errBoom) {
+		t.Fatalf("expected the goroutine's error, got %v", er.ErrRuntime)
+	}
+}
+
+func TestGoDiscardedWhenStartupFails(t *testing.T) {
+	r := New()
+
+	launched := false
+	r.Go(func(ctx context.Context) error {
+		launched = true
+		return nil
+	})
+
+	fail := func(ctx context.Context) error {
+		return errors.New("failed")
+	}
+
+	if er := r.Start([]Func{fail}, nil); er.ErrStartup == nil || launched {
+		t.Fatalf("expected the goroutine to be discarded, got launched=%v and %+v", launched, er)
+	}
+}
+
+func TestGoAwaitedDuringShutdown(t *testing.T) {
+	r := New()
+
+	returned := false
+	r.Go(func(ctx context.Context) error {
+		<-ctx.Done()
+		time.Sleep(20 * time.Millisecond)
+		returned = true
+		return nil
+	})
+	r.Shutdown(nil)
+
+	r.Start(nil, nil)
+	if !returned {
+		t.Fatal("expected Start() to wait for the goroutine")
+	}
+}
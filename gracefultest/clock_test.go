@@ -0,0 +1,55 @@
+package gracefultest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/bryhen/graceful"
+)
+
+func TestClockDrivesShutdownDelay(t *testing.T) {
+	clock := NewClock(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC))
+	r := graceful.New()
+
+	exit := Start(t, r, nil, nil, graceful.WithClock(clock), graceful.WithShutdownDelay(time.Minute))
+	AwaitState(t, r, graceful.StateRunning, 5*time.Second)
+	r.Shutdown(nil)
+
+	// The delay only elapses once the clock is advanced.
+	clock.BlockUntil(1)
+	select {
+	case er := <-exit:
+		t.Fatalf("expected shutdown to wait for the delay, got %v", er)
+	default:
+	}
+
+	clock.Advance(time.Minute)
+	er := <-exit
+	if !er.Clean() || er.Timing.ShutdownDelay != time.Minute {
+		t.Fatalf("expected a clean exit after a 1m delay, got %v", er)
+	}
+}
+
+func TestClockDrivesShutdownTimeout(t *testing.T) {
+	clock := NewClock(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC))
+	r := graceful.New()
+
+	stuck := func(ctx context.Context) error {
+		<-ctx.Done()
+		return context.Cause(ctx)
+	}
+
+	exit := Start(t, r, nil, []graceful.Func{stuck}, graceful.WithClock(clock), graceful.WithShutdownTimeout(time.Minute))
+	AwaitState(t, r, graceful.StateRunning, 5*time.Second)
+	r.Shutdown(nil)
+
+	clock.BlockUntil(1)
+	clock.Advance(time.Minute)
+
+	er := <-exit
+	if !errors.Is(errors.Join(er.ErrsShutdown...), graceful.ErrShutdownTimeout) {
+		t.Fatalf("expected the shutdown timeout to elapse, got %v", er.ErrsShutdown)
+	}
+}
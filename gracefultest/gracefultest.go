@@ -0,0 +1,56 @@
+// Package gracefultest provides helpers for testing code which uses graceful's lifecycle deterministically: a fake
+// clock, lifecycles run in the background, waiting for state transitions, and (with Go 1.25+) running under
+// testing/synctest.
+//
+// Synthetic signals are delivered with graceful.Runner.Signal(), so tests never need to signal the test process:
+//
+//	r := graceful.New()
+//	exit := gracefultest.Start(t, r, startupFns, shutdownFns)
+//	gracefultest.AwaitState(t, r, graceful.StateRunning, time.Second)
+//	r.Signal(syscall.SIGTERM)
+//	er := <-exit
+package gracefultest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bryhen/graceful"
+)
+
+// Runs r.Start() in a new goroutine and returns a channel which receives its ExitReason. If the lifecycle is still
+// running when the test ends, it is shut down with graceful.Runner.Shutdown() and waited on.
+func Start(tb testing.TB, r *graceful.Runner, startupFns []graceful.Func, shutdownFns []graceful.Func, opts ...graceful.Option) <-chan *graceful.ExitReason {
+	tb.Helper()
+
+	exit := make(chan *graceful.ExitReason, 1)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		exit <- r.Start(startupFns, shutdownFns, opts...)
+	}()
+
+	tb.Cleanup(func() {
+		select {
+		case <-done:
+		default:
+			r.Shutdown(nil)
+			<-done
+		}
+	})
+
+	return exit
+}
+
+// Blocks until r is in state want, failing the test if it is not within timeout.
+func AwaitState(tb testing.TB, r *graceful.Runner, want graceful.State, timeout time.Duration) {
+	tb.Helper()
+
+	deadline := time.Now().Add(timeout)
+	for r.State() != want {
+		if time.Now().After(deadline) {
+			tb.Fatalf("gracefultest: state is %v after %v, want %v", r.State(), timeout, want)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
@@ -0,0 +1,27 @@
+//go:build go1.25
+
+package gracefultest
+
+import (
+	"os"
+	"os/signal"
+	"testing"
+	"testing/synctest"
+)
+
+// Runs f in a testing/synctest bubble, where time is virtual and only advances once every goroutine in the bubble is
+// blocked. Timeouts, the shutdown delay, and restart backoff then elapse instantly and deterministically, without
+// graceful.WithClock().
+//
+// The Runner must be created inside f with graceful.New(), since the bubble may not share channels with the default
+// Runner. Deliver signals with graceful.Runner.Signal().
+func Run(t *testing.T, f func(t *testing.T)) {
+	t.Helper()
+
+	// os/signal starts its delivery goroutine on first use. It never exits, so it must not start inside the bubble.
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, os.Interrupt)
+	signal.Stop(ch)
+
+	synctest.Test(t, f)
+}
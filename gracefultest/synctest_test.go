@@ -0,0 +1,40 @@
+//go:build go1.25
+
+package gracefultest
+
+import (
+	"context"
+	"errors"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/bryhen/graceful"
+)
+
+func TestRun(t *testing.T) {
+	Run(t, func(t *testing.T) {
+		r := graceful.New()
+
+		stuck := func(ctx context.Context) error {
+			<-ctx.Done()
+			return context.Cause(ctx)
+		}
+
+		go func() {
+			time.Sleep(time.Second)
+			r.Signal(syscall.SIGTERM)
+		}()
+
+		// An hour of shutdown delay and timeout elapse instantly in the bubble.
+		er := r.Start(nil, []graceful.Func{stuck}, graceful.WithShutdownDelay(time.Hour),
+			graceful.WithShutdownTimeout(time.Hour))
+
+		if er.OsSignal != syscall.SIGTERM {
+			t.Fatalf("expected SIGTERM, got %v", er.OsSignal)
+		}
+		if !errors.Is(errors.Join(er.ErrsShutdown...), graceful.ErrShutdownTimeout) {
+			t.Fatalf("expected the shutdown timeout to elapse, got %v", er.ErrsShutdown)
+		}
+	})
+}
@@ -0,0 +1,269 @@
+package graceful
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+)
+
+// Declares components and the components they depend on, then starts them with maximal parallelism: each component is
+// started as soon as everything it depends on has started. Shutdown is the reverse, each component is stopped once
+// everything which depends on it has stopped.
+//
+//	g := graceful.NewGraph().
+//		Provide("db", db.Connect, graceful.OnStop(db.Close)).
+//		Provide("cache", cache.Connect, graceful.OnStop(cache.Close), graceful.After("db")).
+//		Provide("server", server.Listen, graceful.OnStop(server.Close), graceful.After("db", "cache"))
+//	startup, shutdown := g.Funcs()
+//	graceful.Start([]graceful.Func{startup}, []graceful.Func{shutdown})
+type Graph struct {
+	mu      sync.Mutex
+	nodes   []*graphNode
+	started map[string]bool
+}
+
+type graphNode struct {
+	name  string
+	start Func
+	stop  Func
+	after []string
+}
+
+// Configures a component provided to Graph.Provide().
+type NodeOption func(*graphNode)
+
+// The component is started only after the named components have started, and is stopped before them.
+func After(names ...string) NodeOption {
+	return func(n *graphNode) {
+		n.after = append(n.after, names...)
+	}
+}
+
+// Function which stops the component. It is only run if the component's start function succeeded. Default: none.
+func OnStop(fn Func) NodeOption {
+	return func(n *graphNode) {
+		n.stop = fn
+	}
+}
+
+// Creates an empty Graph.
+func NewGraph() *Graph {
+	return &Graph{}
+}
+
+// Adds a component. start may be nil for components which only need stopping. Names must be unique, which is checked
+// (along with unknown dependencies and cycles) when the startup function returned by Funcs() runs.
+func (g *Graph) Provide(name string, start Func, opts ...NodeOption) *Graph {
+	n := &graphNode{name: name, start: start}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(n)
+		}
+	}
+
+	g.mu.Lock()
+	g.nodes = append(g.nodes, n)
+	g.mu.Unlock()
+
+	return g
+}
+
+// Returns the functions which start and stop the graph.
+//
+// If a component fails to start, no further components are started, those already running are waited on, and every
+// error is reported, combined with errors.Join(). Errors are attributed to the component's name. The shutdown function
+// only stops the components which started, so it can also be used as a rollback with WithStartupRollback().
+func (g *Graph) Funcs() (startup Func, shutdown Func) {
+	return g.startup, g.shutdown
+}
+
+// Reports duplicate names, unknown dependencies, and cycles without starting anything. The startup function returned
+// by Funcs() performs the same checks before starting any component.
+func (g *Graph) Validate() error {
+	g.mu.Lock()
+	nodes := slices.Clone(g.nodes)
+	g.mu.Unlock()
+
+	return validateGraph(nodes)
+}
+
+func (g *Graph) startup(ctx context.Context) error {
+	g.mu.Lock()
+	nodes := slices.Clone(g.nodes)
+	g.started = map[string]bool{}
+	g.mu.Unlock()
+
+	if err := validateGraph(nodes); err != nil {
+		return err
+	}
+
+	// Dependents of each node, and the number of dependencies each node is still waiting on
+	dependents := map[string][]*graphNode{}
+	waiting := map[string]int{}
+	for _, n := range nodes {
+		waiting[n.name] = len(n.after)
+		for _, dep := range n.after {
+			dependents[dep] = append(dependents[dep], n)
+		}
+	}
+
+	return walkGraph(ctx, nodes, waiting, dependents, true, func(n *graphNode) Func {
+		return n.start
+	}, func(n *graphNode) {
+		g.mu.Lock()
+		g.started[n.name] = true
+		g.mu.Unlock()
+	})
+}
+
+func (g *Graph) shutdown(ctx context.Context) error {
+	g.mu.Lock()
+	var nodes []*graphNode
+	for _, n := range g.nodes {
+		if g.started[n.name] {
+			nodes = append(nodes, n)
+		}
+	}
+	g.started = nil
+	g.mu.Unlock()
+
+	// Reverse the edges: a node is stopped once every started node which depends on it has stopped.
+	dependents := map[string][]*graphNode{}
+	waiting := map[string]int{}
+	byName := map[string]*graphNode{}
+	for _, n := range nodes {
+		byName[n.name] = n
+	}
+	for _, n := range nodes {
+		for _, dep := range n.after {
+			if d, ok := byName[dep]; ok {
+				waiting[d.name]++
+				dependents[n.name] = append(dependents[n.name], d)
+			}
+		}
+	}
+
+	return walkGraph(ctx, nodes, waiting, dependents, false, func(n *graphNode) Func {
+		return n.stop
+	}, nil)
+}
+
+type graphResult struct {
+	node *graphNode
+	err  error
+}
+
+// Runs fn for every node once the number of nodes it is waiting on reaches zero. If failFast, no further nodes are run
+// after an error; otherwise the nodes waiting on a failed node are still run.
+func walkGraph(ctx context.Context, nodes []*graphNode, waiting map[string]int,
+	dependents map[string][]*graphNode, failFast bool, fn func(*graphNode) Func, done func(*graphNode)) error {
+
+	results := make(chan graphResult, len(nodes))
+	running := 0
+
+	launch := func(n *graphNode) {
+		running++
+		go func() {
+			var err error
+			if f := fn(n); f != nil {
+				// The node's name identifies it, so nested Named() functions must not report theirs.
+				if err = f(context.WithValue(ctx, funcNameKey{}, (*funcNameSlot)(nil))); err != nil {
+					err = &FuncError{Name: n.name, Err: err}
+				}
+			}
+			results <- graphResult{node: n, err: err}
+		}()
+	}
+
+	for _, n := range nodes {
+		if waiting[n.name] == 0 {
+			launch(n)
+		}
+	}
+
+	var errs []error
+	for running > 0 {
+		res := <-results
+		running--
+
+		if res.err != nil {
+			errs = append(errs, res.err)
+		} else if done != nil {
+			done(res.node)
+		}
+
+		if failFast && (len(errs) > 0 || ctx.Err() != nil) {
+			continue
+		}
+
+		for _, d := range dependents[res.node.name] {
+			if waiting[d.name]--; waiting[d.name] == 0 {
+				launch(d)
+			}
+		}
+	}
+
+	if failFast && len(errs) == 0 {
+		if err := ctx.Err(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Reports duplicate names, unknown dependencies, and cycles.
+func validateGraph(nodes []*graphNode) error {
+	byName := map[string]*graphNode{}
+	for _, n := range nodes {
+		if _, ok := byName[n.name]; ok {
+			return fmt.Errorf("graph: duplicate component %q", n.name)
+		}
+		byName[n.name] = n
+	}
+
+	for _, n := range nodes {
+		for _, dep := range n.after {
+			if _, ok := byName[dep]; !ok {
+				return fmt.Errorf("graph: component %q depends on unknown component %q", n.name, dep)
+			}
+		}
+	}
+
+	// Depth-first search, where a node still on the stack being reached again is a cycle
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	marks := map[string]int{}
+	var visit func(n *graphNode, path []string) error
+	visit = func(n *graphNode, path []string) error {
+		switch marks[n.name] {
+		case visiting:
+			start := slices.Index(path, n.name)
+			return fmt.Errorf("graph: dependency cycle %v", append(path[start:], n.name))
+		case visited:
+			return nil
+		}
+
+		marks[n.name] = visiting
+		for _, dep := range n.after {
+			if err := visit(byName[dep], append(path, n.name)); err != nil {
+				return err
+			}
+		}
+		marks[n.name] = visited
+		return nil
+	}
+
+	for _, n := range nodes {
+		if err := visit(n, nil); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
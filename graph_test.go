@@ -0,0 +1,97 @@
+package graceful
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestGraphOrder(t *testing.T) {
+	var mu sync.Mutex
+	var order []string
+	step := func(name string) Func {
+		return func(ctx context.Context) error {
+			mu.Lock()
+			defer mu.Unlock()
+			order = append(order, name)
+			return nil
+		}
+	}
+
+	g := NewGraph().
+		Provide("server", step("start server"), OnStop(step("stop server")), After("db", "cache")).
+		Provide("cache", step("start cache"), OnStop(step("stop cache")), After("db")).
+		Provide("db", step("start db"), OnStop(step("stop db")))
+	startup, shutdown := g.Funcs()
+
+	r := New()
+	r.Shutdown(nil)
+	if er := r.Start([]Func{startup}, []Func{shutdown}); !er.Clean() {
+		t.Fatalf("expected a clean exit, got %v", er)
+	}
+
+	want := []string{"start db", "start cache", "start server", "stop server", "stop cache", "stop db"}
+	if !slices.Equal(order, want) {
+		t.Fatalf("expected %v, got %v", want, order)
+	}
+}
+
+func TestGraphStopsOnlyStarted(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	var stopped []string
+	stop := func(name string) Func {
+		return func(ctx context.Context) error {
+			stopped = append(stopped, name)
+			return nil
+		}
+	}
+	ok := func(ctx context.Context) error { return nil }
+	fail := func(ctx context.Context) error { return errBoom }
+
+	g := NewGraph().
+		Provide("db", ok, OnStop(stop("db"))).
+		Provide("cache", fail, OnStop(stop("cache")), After("db")).
+		Provide("server", ok, OnStop(stop("server")), After("cache"))
+	startup, shutdown := g.Funcs()
+
+	err := startup(context.Background())
+
+	var fe *FuncError
+	if !errors.As(err, &fe) || fe.Name != "cache" || !errors.Is(fe, errBoom) {
+		t.Fatalf("expected the cache to fail, got %v", err)
+	}
+	if err := shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(stopped, []string{"db"}) {
+		t.Fatalf("expected only the started db to be stopped, got %v", stopped)
+	}
+}
+
+func TestGraphValidate(t *testing.T) {
+	tests := []struct {
+		name string
+		g    *Graph
+		want string
+	}{
+		{"duplicate", NewGraph().Provide("a", nil).Provide("a", nil), "duplicate"},
+		{"unknown", NewGraph().Provide("a", nil, After("b")), "unknown"},
+		{"cycle", NewGraph().Provide("a", nil, After("b")).Provide("b", nil, After("a")), "cycle"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.g.Validate(); err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("expected a %s error, got %v", tt.want, err)
+			}
+		})
+	}
+
+	if err := NewGraph().Provide("a", nil).Provide("b", nil, After("a")).Validate(); err != nil {
+		t.Fatalf("expected a valid graph, got %v", err)
+	}
+}
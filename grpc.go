@@ -0,0 +1,44 @@
+package graceful
+
+import (
+	"context"
+	"net"
+)
+
+// The subset of *grpc.Server used by GRPCServer(), so this package does not depend on gRPC.
+type GRPCServerStopper interface {
+	Serve(lis net.Listener) error
+	GracefulStop()
+	Stop()
+}
+
+// Adapts srv (usually a *grpc.Server) to the lifecycle. Pass run to WithRun() and stop to the shutdown functions.
+//
+// run serves on ln until srv is stopped.
+//
+// stop calls GracefulStop(), which waits for pending RPCs to finish. If the shutdown context is done first, it escalates
+// to Stop(), which closes all connections and cancels pending RPCs, and returns the context's error.
+func GRPCServer(srv GRPCServerStopper, ln net.Listener) (run Func, stop Func) {
+	run = func(ctx context.Context) error {
+		return srv.Serve(ln)
+	}
+
+	stop = func(ctx context.Context) error {
+		done := make(chan struct{})
+		go func() {
+			srv.GracefulStop()
+			close(done)
+		}()
+
+		select {
+		case <-done:
+			return nil
+		case <-ctx.Done():
+			srv.Stop()
+			<-done
+			return ctx.Err()
+		}
+	}
+
+	return run, stop
+}
@@ -0,0 +1,59 @@
+package graceful
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+)
+
+// Mimics *grpc.Server: GracefulStop() waits for pending RPCs, which Stop() cancels.
+type fakeGRPCServer struct {
+	stopped chan struct{}
+	pending chan struct{}
+	forced  bool
+}
+
+func newFakeGRPCServer() *fakeGRPCServer {
+	return &fakeGRPCServer{stopped: make(chan struct{}), pending: make(chan struct{})}
+}
+
+func (s *fakeGRPCServer) Serve(lis net.Listener) error {
+	<-s.stopped
+	return nil
+}
+
+func (s *fakeGRPCServer) GracefulStop() {
+	close(s.stopped)
+	<-s.pending
+}
+
+func (s *fakeGRPCServer) Stop() {
+	s.forced = true
+	close(s.pending)
+}
+
+func TestGRPCServerGracefulStop(t *testing.T) {
+	srv := newFakeGRPCServer()
+	close(srv.pending)
+	run, stop := GRPCServer(srv, nil)
+
+	r := New()
+	r.Shutdown(nil)
+	er := r.Start(nil, []Func{stop}, WithRun(run))
+	if er.ErrRuntime != nil || len(er.ErrsShutdown) != 0 || srv.forced {
+		t.Fatalf("expected a graceful stop, got %v", er)
+	}
+}
+
+func TestGRPCServerEscalatesToStop(t *testing.T) {
+	srv := newFakeGRPCServer()
+	_, stop := GRPCServer(srv, nil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := stop(ctx); !errors.Is(err, context.DeadlineExceeded) || !srv.forced {
+		t.Fatalf("expected Stop() once the context is done, got %v", err)
+	}
+}
@@ -0,0 +1,97 @@
+package graceful
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"runtime/metrics"
+	"runtime/pprof"
+	"time"
+)
+
+// Reported in ExitReason.ErrRuntime (wrapped, describing the exceeded limit) when a limit given to
+// WithResourceGuard() is exceeded.
+var ErrResourceLimit = errors.New("resource limit exceeded")
+
+// Thresholds checked by WithResourceGuard(). A zero limit is not checked.
+type ResourceLimits struct {
+	MaxHeapBytes uint64 // Bytes occupied by live and not yet swept heap objects
+	MaxRSSBytes  uint64 // Resident set size of the process. Only checked on Linux.
+	MaxOpenFiles int    // Open file descriptors of the process. Only checked on Linux.
+
+	// If set, a heap profile (in the format of runtime/pprof) is written to it before shutdown is triggered.
+	HeapProfile io.Writer
+}
+
+// Samples the process every interval during the run phase and triggers shutdown with ErrResourceLimit once a limit is
+// exceeded, so a supervisor can restart a leaking service before the OOM killer does so uncleanly. Default: no guard.
+func WithResourceGuard(interval time.Duration, limits ResourceLimits) Option {
+	return func(config *config) error {
+		if interval <= 0 {
+			return invalidOption("resource guard interval must be positive")
+		}
+		config.guardInterval = interval
+		config.guardLimits = limits
+		return nil
+	}
+}
+
+// Launches the resource guard, which returns once ctx is done.
+func (r *Runner) guardResources(ctx context.Context, config *config) {
+	if config.guardInterval == 0 {
+		return
+	}
+
+	go func() {
+		for {
+			select {
+			case <-config.after(config.guardInterval):
+			case <-ctx.Done():
+				return
+			}
+
+			err := checkResources(config.guardLimits)
+			if err == nil {
+				continue
+			}
+
+			config.log(slog.LevelError, "resource limit exceeded, shutting down", slog.Any("err", err))
+			if w := config.guardLimits.HeapProfile; w != nil {
+				if perr := pprof.Lookup("heap").WriteTo(w, 0); perr != nil {
+					config.log(slog.LevelError, "failed to write heap profile", slog.Any("err", perr))
+				}
+			}
+			r.ShutdownWithCause(err, "resource guard")
+			return
+		}
+	}()
+}
+
+// Returns an error describing the first exceeded limit, if any.
+func checkResources(limits ResourceLimits) error {
+	if limits.MaxHeapBytes > 0 {
+		sample := []metrics.Sample{{Name: "/memory/classes/heap/objects:bytes"}}
+		metrics.Read(sample)
+		if sample[0].Value.Kind() == metrics.KindUint64 {
+			if heap := sample[0].Value.Uint64(); heap > limits.MaxHeapBytes {
+				return fmt.Errorf("%w: heap %d bytes > %d", ErrResourceLimit, heap, limits.MaxHeapBytes)
+			}
+		}
+	}
+
+	if limits.MaxRSSBytes > 0 {
+		if rss, ok := readRSS(); ok && rss > limits.MaxRSSBytes {
+			return fmt.Errorf("%w: rss %d bytes > %d", ErrResourceLimit, rss, limits.MaxRSSBytes)
+		}
+	}
+
+	if limits.MaxOpenFiles > 0 {
+		if n, ok := countOpenFiles(); ok && n > limits.MaxOpenFiles {
+			return fmt.Errorf("%w: %d open files > %d", ErrResourceLimit, n, limits.MaxOpenFiles)
+		}
+	}
+
+	return nil
+}
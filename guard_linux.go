@@ -0,0 +1,31 @@
+//go:build linux
+
+package graceful
+
+import (
+	"fmt"
+	"os"
+)
+
+// Reads the resident set size from /proc/self/statm, which reports it in pages.
+func readRSS() (uint64, bool) {
+	b, err := os.ReadFile("/proc/self/statm")
+	if err != nil {
+		return 0, false
+	}
+
+	var size, resident uint64
+	if n, _ := fmt.Sscan(string(b), &size, &resident); n != 2 {
+		return 0, false
+	}
+	return resident * uint64(os.Getpagesize()), true
+}
+
+func countOpenFiles() (int, bool) {
+	entries, err := os.ReadDir("/proc/self/fd")
+	if err != nil {
+		return 0, false
+	}
+	// One of the entries is the descriptor ReadDir opened to list the directory.
+	return len(entries) - 1, true
+}
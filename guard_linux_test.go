@@ -0,0 +1,21 @@
+//go:build linux
+
+package graceful
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCheckResourcesLinux(t *testing.T) {
+	if rss, ok := readRSS(); !ok || rss == 0 {
+		t.Fatalf("expected the rss to be read, got %d (%v)", rss, ok)
+	}
+	if n, ok := countOpenFiles(); !ok || n < 3 {
+		t.Fatalf("expected at least the standard descriptors to be open, got %d (%v)", n, ok)
+	}
+
+	if err := checkResources(ResourceLimits{MaxOpenFiles: 1}); !errors.Is(err, ErrResourceLimit) {
+		t.Fatalf("expected the open files limit to be exceeded, got %v", err)
+	}
+}
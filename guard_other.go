@@ -0,0 +1,11 @@
+//go:build !linux
+
+package graceful
+
+func readRSS() (uint64, bool) {
+	return 0, false
+}
+
+func countOpenFiles() (int, bool) {
+	return 0, false
+}
@@ -0,0 +1,35 @@
+package graceful
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestResourceGuard(t *testing.T) {
+	var profile bytes.Buffer
+	limits := ResourceLimits{MaxHeapBytes: 1, HeapProfile: &profile}
+
+	er := New().Start(nil, nil, WithRun(func(ctx context.Context) error {
+		<-ctx.Done()
+		return nil
+	}), WithResourceGuard(time.Millisecond, limits))
+
+	if !errors.Is(er.ErrRuntime, ErrResourceLimit) || er.Reason != "resource guard" {
+		t.Fatalf("expected a resource limit error, got %v (%q)", er.ErrRuntime, er.Reason)
+	}
+	if profile.Len() == 0 {
+		t.Fatalf("expected a heap profile to be written")
+	}
+}
+
+func TestCheckResources(t *testing.T) {
+	if err := checkResources(ResourceLimits{}); err != nil {
+		t.Fatalf("expected zero limits to be unchecked, got %v", err)
+	}
+	if err := checkResources(ResourceLimits{MaxHeapBytes: 1 << 62, MaxRSSBytes: 1 << 62, MaxOpenFiles: 1 << 30}); err != nil {
+		t.Fatalf("expected generous limits to pass, got %v", err)
+	}
+}
@@ -0,0 +1,70 @@
+package graceful
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Tracks sections of application code which hold up part of the lifecycle, see Inhibit() and Protect().
+type holdSet struct {
+	mu       sync.Mutex
+	holds    map[*hold]struct{}
+	released chan struct{}
+}
+
+type hold struct {
+	reason string
+	since  time.Time
+}
+
+// Adds a hold which lasts until release is called or ctx is done. release is safe to call more than once. The hold's
+// start is measured by the Clock given to WithClock() if ctx belongs to the lifecycle.
+func (hs *holdSet) acquire(ctx context.Context, reason string) (release func()) {
+	h := &hold{reason: reason, since: nowFrom(ctx)}
+
+	hs.mu.Lock()
+	if hs.holds == nil {
+		hs.holds = map[*hold]struct{}{}
+	}
+	hs.holds[h] = struct{}{}
+	hs.mu.Unlock()
+
+	var once sync.Once
+	remove := func() {
+		once.Do(func() {
+			hs.mu.Lock()
+			delete(hs.holds, h)
+			if hs.released != nil {
+				close(hs.released)
+				hs.released = nil
+			}
+			hs.mu.Unlock()
+		})
+	}
+	stop := context.AfterFunc(ctx, remove)
+
+	return func() {
+		stop()
+		remove()
+	}
+}
+
+// Returns the active holds, and a channel which is closed when one of them is released.
+func (hs *holdSet) active() ([]hold, <-chan struct{}) {
+	hs.mu.Lock()
+	defer hs.mu.Unlock()
+
+	if len(hs.holds) == 0 {
+		return nil, nil
+	}
+
+	active := make([]hold, 0, len(hs.holds))
+	for h := range hs.holds {
+		active = append(active, *h)
+	}
+	if hs.released == nil {
+		hs.released = make(chan struct{})
+	}
+	return active, hs.released
+}
@@ -0,0 +1,53 @@
+package graceful
+
+import "context"
+
+// A component which is started during startup and stopped during shutdown.
+type Hook interface {
+	Start(ctx context.Context) error
+	Stop(ctx context.Context) error
+}
+
+type hook struct {
+	start Func
+	stop  Func
+}
+
+func (h *hook) Start(ctx context.Context) error {
+	if h.start == nil {
+		return nil
+	}
+	return h.start(ctx)
+}
+
+func (h *hook) Stop(ctx context.Context) error {
+	if h.stop == nil {
+		return nil
+	}
+	return h.stop(ctx)
+}
+
+// Creates a Hook from a pair of functions. Either may be nil.
+func NewHook(start Func, stop Func) Hook {
+	return &hook{
+		start: start,
+		stop:  stop,
+	}
+}
+
+// Converts hooks into startup and shutdown functions which can be passed directly to Start():
+//
+//	graceful.Start(graceful.Hooks(db, cache, server))
+//
+// Hooks are started in registration order and stopped in reverse registration order.
+func Hooks(hooks ...Hook) (startupFns []Func, shutdownFns []Func) {
+	for _, h := range hooks {
+		startupFns = append(startupFns, h.Start)
+	}
+
+	for i := len(hooks) - 1; i >= 0; i-- {
+		shutdownFns = append(shutdownFns, hooks[i].Stop)
+	}
+
+	return startupFns, shutdownFns
+}
@@ -0,0 +1,34 @@
+package graceful
+
+import (
+	"context"
+	"slices"
+	"testing"
+)
+
+func TestHooksOrder(t *testing.T) {
+	var calls []string
+	record := func(call string) Func {
+		return func(ctx context.Context) error {
+			calls = append(calls, call)
+			return nil
+		}
+	}
+
+	startupFns, shutdownFns := Hooks(
+		NewHook(record("start a"), record("stop a")),
+		NewHook(record("start b"), nil),
+		NewHook(nil, record("stop c")),
+	)
+
+	r := New()
+	r.Shutdown(nil)
+	if er := r.Start(startupFns, shutdownFns); er.ErrStartup != nil || er.ErrRuntime != nil || len(er.ErrsShutdown) != 0 {
+		t.Fatalf("expected a clean exit, got %+v", er)
+	}
+
+	want := []string{"start a", "start b", "stop c", "stop a"}
+	if !slices.Equal(calls, want) {
+		t.Fatalf("expected %q, got %q", want, calls)
+	}
+}
@@ -0,0 +1,43 @@
+package graceful
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+)
+
+// Adapts srv to the lifecycle. Pass run to WithRun() and stop to the shutdown functions:
+//
+//	run, stop := graceful.HTTPServer(srv, ln)
+//	graceful.Start(startupFns, []graceful.Func{stop}, graceful.WithRun(run))
+//
+// run serves on ln, or listens on srv.Addr if ln is nil. http.ErrServerClosed is treated as a clean exit.
+//
+// stop gracefully shuts srv down. If the shutdown context is done before all connections are idle, the remaining
+// connections are closed forcibly and the context's error is returned.
+func HTTPServer(srv *http.Server, ln net.Listener) (run Func, stop Func) {
+	run = func(ctx context.Context) error {
+		var err error
+		if ln != nil {
+			err = srv.Serve(ln)
+		} else {
+			err = srv.ListenAndServe()
+		}
+
+		if errors.Is(err, http.ErrServerClosed) {
+			return nil
+		}
+		return err
+	}
+
+	stop = func(ctx context.Context) error {
+		if err := srv.Shutdown(ctx); err != nil {
+			srv.Close()
+			return err
+		}
+		return nil
+	}
+
+	return run, stop
+}
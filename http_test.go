@@ -0,0 +1,84 @@
+package graceful
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func listenLocal(t *testing.T) net.Listener {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("cannot listen: %v", err)
+	}
+	return ln
+}
+
+func TestHTTPServerWaitsForInFlightRequests(t *testing.T) {
+	ln := listenLocal(t)
+
+	started := make(chan struct{})
+	srv := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		close(started)
+		time.Sleep(50 * time.Millisecond)
+		io.WriteString(w, "done")
+	})}
+	run, stop := HTTPServer(srv, ln)
+
+	body := make(chan string, 1)
+	go func() {
+		resp, err := http.Get("http://" + ln.Addr().String())
+		if err != nil {
+			body <- err.Error()
+			return
+		}
+		defer resp.Body.Close()
+		b, _ := io.ReadAll(resp.Body)
+		body <- string(b)
+	}()
+
+	r := New()
+	go func() {
+		<-started
+		r.Shutdown(nil)
+	}()
+
+	er := r.Start(nil, []Func{stop}, WithRun(run))
+	if er.ErrRuntime != nil || len(er.ErrsShutdown) != 0 {
+		t.Fatalf("expected a clean exit, got %v", er)
+	}
+	if got := <-body; got != "done" {
+		t.Fatalf("expected the in-flight request to complete, got %q", got)
+	}
+}
+
+func TestHTTPServerStopClosesWhenContextDone(t *testing.T) {
+	ln := listenLocal(t)
+
+	started := make(chan struct{})
+	srv := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		close(started)
+		<-req.Context().Done()
+	})}
+	run, stop := HTTPServer(srv, ln)
+
+	served := make(chan error, 1)
+	go func() { served <- run(context.Background()) }()
+	go http.Get("http://" + ln.Addr().String())
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := stop(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected the shutdown context's error, got %v", err)
+	}
+	if err := <-served; err != nil {
+		t.Fatalf("expected run to treat the closed server as a clean exit, got %v", err)
+	}
+}
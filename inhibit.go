@@ -0,0 +1,80 @@
+package graceful
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+// Default for WithMaxInhibit().
+const defaultMaxInhibit = 30 * time.Second
+
+// Delays the start of shutdown until release is called, so a non-interruptible operation (ie committing a payment) can
+// finish. release is also called when ctx is done, and is safe to call more than once. How long it has been held is
+// measured by the Clock given to WithClock() if ctx belongs to the lifecycle.
+//
+// Once shutdown is triggered, the shutdown functions (and the shutdown delay) wait for every inhibitor to be released,
+// for at most the duration given to WithMaxInhibit(). Readiness is already off while waiting. The active inhibitors and
+// how long they have been held are logged and emitted as EventShutdownInhibited.
+//
+//	release := graceful.Inhibit(ctx, "payment commit")
+//	defer release()
+func Inhibit(ctx context.Context, reason string) (release func()) {
+	return defaultRunner.Inhibit(ctx, reason)
+}
+
+// Same as the package-level Inhibit(), but scoped to this Runner.
+func (r *Runner) Inhibit(ctx context.Context, reason string) (release func()) {
+	return r.inhibitors.acquire(ctx, reason)
+}
+
+// Blocks until every inhibitor is released or config.maxInhibit elapses. Returns how long it waited.
+func (r *Runner) awaitInhibitors(config *config) time.Duration {
+	active, released := r.inhibitors.active()
+	if len(active) == 0 || config.maxInhibit == 0 {
+		return 0
+	}
+
+	now := config.now()
+	for _, inh := range active {
+		config.log(slog.LevelInfo, "shutdown inhibited", slog.String("reason", inh.reason),
+			slog.Duration("held", now.Sub(inh.since)))
+		r.emit(Event{Type: EventShutdownInhibited, Name: inh.reason, Duration: now.Sub(inh.since)})
+	}
+
+	tCtx, tCancel := config.withTimeout(context.Background(), config.maxInhibit)
+	defer tCancel()
+
+	start := config.now()
+	for len(active) > 0 {
+		select {
+		case <-released:
+		case <-tCtx.Done():
+			reasons := make([]string, len(active))
+			for i, inh := range active {
+				reasons[i] = inh.reason
+			}
+			config.log(slog.LevelWarn, "max inhibit exceeded, shutting down anyway",
+				slog.Duration("max", config.maxInhibit), slog.String("reasons", strings.Join(reasons, ", ")))
+			return config.since(start)
+		}
+		active, released = r.inhibitors.active()
+	}
+
+	waited := config.since(start)
+	config.log(slog.LevelInfo, "shutdown inhibitors released", slog.Duration("waited", waited))
+	return waited
+}
+
+// Maximum amount of time shutdown waits for the inhibitors acquired with Inhibit() to be released. Zero ignores
+// inhibitors. Default: 30s.
+func WithMaxInhibit(d time.Duration) Option {
+	return func(config *config) error {
+		if d < 0 {
+			return invalidOption("max inhibit must not be negative")
+		}
+		config.maxInhibit = d
+		return nil
+	}
+}
@@ -0,0 +1,85 @@
+package graceful
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestInhibit(t *testing.T) {
+	r := New()
+
+	released := false
+	critical := func(ctx context.Context) error {
+		release := r.Inhibit(context.Background(), "commit")
+		go func() {
+			time.Sleep(50 * time.Millisecond)
+			released = true
+			release()
+		}()
+		return nil
+	}
+
+	releasedFirst := false
+	shutdown := func(ctx context.Context) error {
+		releasedFirst = released
+		return nil
+	}
+
+	r.Shutdown(nil)
+	er := r.Start([]Func{critical}, []Func{shutdown})
+
+	if !releasedFirst || er.Timing.Inhibited <= 0 {
+		t.Fatalf("expected shutdown to wait for the inhibitor, got released first=%v inhibited=%s", releasedFirst,
+			er.Timing.Inhibited)
+	}
+}
+
+func TestInhibitMax(t *testing.T) {
+	r := New()
+
+	critical := func(ctx context.Context) error {
+		r.Inhibit(context.Background(), "stuck")
+		return nil
+	}
+
+	r.Shutdown(nil)
+	er := r.Start([]Func{critical}, nil, WithMaxInhibit(20*time.Millisecond))
+
+	if er.Timing.Inhibited < 20*time.Millisecond || er.Timing.Inhibited > time.Second {
+		t.Fatalf("expected shutdown to wait for the max inhibit, got %s", er.Timing.Inhibited)
+	}
+}
+
+func TestInhibitReleasedByContext(t *testing.T) {
+	r := New()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// ctx is already done, so release races with the context's own release.
+	release := r.Inhibit(ctx, "canceled")
+	release()
+	release()
+
+	if active, _ := r.inhibitors.active(); len(active) != 0 {
+		t.Fatalf("expected no active inhibitors, got %v", active)
+	}
+}
+
+// A clock which is stopped at a fixed time.
+type fixedClock time.Time
+
+func (fc fixedClock) Now() time.Time                         { return time.Time(fc) }
+func (fc fixedClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+
+func TestInhibitUsesClock(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	r := New()
+	release := r.Inhibit(context.WithValue(context.Background(), clockKey{}, Clock(fixedClock(start))), "clocked")
+	defer release()
+
+	if active, _ := r.inhibitors.active(); len(active) != 1 || !active[0].since.Equal(start) {
+		t.Fatalf("expected the inhibitor to be timed by the clock, got %v", active)
+	}
+}
@@ -0,0 +1,63 @@
+package graceful
+
+import (
+	"os"
+	"slices"
+	"sync"
+)
+
+// Process groups which receive forwarded shutdown signals in init mode.
+var (
+	pgMu   sync.Mutex
+	pgRegs []int
+)
+
+// Registers a child process group to receive the shutdown signal when WithInitMode() is used. Usually the child's pid,
+// if it was started with SysProcAttr.Setpgid.
+func ManageProcessGroup(pgid int) {
+	pgMu.Lock()
+	defer pgMu.Unlock()
+
+	pgRegs = append(pgRegs, pgid)
+}
+
+// Stops forwarding shutdown signals to a process group registered with ManageProcessGroup().
+func UnmanageProcessGroup(pgid int) {
+	pgMu.Lock()
+	defer pgMu.Unlock()
+
+	pgRegs = slices.DeleteFunc(pgRegs, func(p int) bool { return p == pgid })
+}
+
+// Sends sig to every managed process group. Does nothing unless WithInitMode() was provided.
+func forwardSignal(config *config, sig os.Signal) {
+	if !config.initMode {
+		return
+	}
+
+	pgMu.Lock()
+	pgids := slices.Clone(pgRegs)
+	pgMu.Unlock()
+
+	for _, pgid := range pgids {
+		signalGroup(pgid, sig)
+	}
+}
+
+// Allows the application to run as a container entrypoint (PID 1):
+//
+//   - Orphaned child processes are reaped when SIGCHLD is received, so they do not accumulate as zombies.
+//   - The shutdown signal is forwarded to the process groups registered with ManageProcessGroup() before the shutdown
+//     functions are run.
+//
+// Reaping collects the exit status of every child, so exec.Cmd.Wait() may fail for children started by the
+// application itself. Children started by Upgrade() are unaffected. Only supported on Unix, except AIX. Default: off.
+func WithInitMode() Option {
+	return func(config *config) error {
+		if !initModeSupported {
+			return errInitModeUnsupported
+		}
+		config.initMode = true
+		return nil
+	}
+}
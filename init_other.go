@@ -0,0 +1,25 @@
+//go:build !unix || aix
+
+package graceful
+
+import (
+	"os"
+	"os/exec"
+)
+
+const initModeSupported = false
+
+var errInitModeUnsupported = invalidOption("init mode is only supported on unix, except aix")
+
+func startReaper() (stop func()) {
+	return nop
+}
+
+func signalGroup(pgid int, sig os.Signal) {}
+
+func startChild(cmd *exec.Cmd) (wait func() error, err error) {
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+	return cmd.Wait, nil
+}
@@ -0,0 +1,14 @@
+//go:build !unix || aix
+
+package graceful
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestInitModeUnsupported(t *testing.T) {
+	if err := parseOptions(&config{}, []Option{WithInitMode()}); !errors.Is(err, ErrInvalidOption) {
+		t.Fatalf("expected ErrInvalidOption, got %v", err)
+	}
+}
@@ -0,0 +1,121 @@
+//go:build unix && !aix
+
+package graceful
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"sync"
+	"syscall"
+)
+
+const initModeSupported = true
+
+var errInitModeUnsupported error
+
+// Reaps child processes whenever SIGCHLD is received until the returned function is called.
+func startReaper() (stop func()) {
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, syscall.SIGCHLD)
+	done := make(chan struct{})
+
+	go func() {
+		for {
+			select {
+			case <-sigs:
+				reap()
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return func() {
+		signal.Stop(sigs)
+		close(done)
+	}
+}
+
+// Children started with startChild(), whose exit status the reaper hands over to their waiter instead of discarding it.
+var (
+	childMu  sync.Mutex
+	children = map[int]chan syscall.WaitStatus{}
+)
+
+// Collects the exit status of every child which has exited.
+func reap() {
+	childMu.Lock()
+	defer childMu.Unlock()
+
+	for {
+		var ws syscall.WaitStatus
+		pid, err := syscall.Wait4(-1, &ws, syscall.WNOHANG, nil)
+		if pid <= 0 || err != nil {
+			return
+		}
+		if status, ok := children[pid]; ok {
+			select {
+			case status <- ws:
+			default:
+			}
+		}
+	}
+}
+
+// Same as cmd.Start(), but returns a function to use in place of cmd.Wait(). If the reaper collected the child's exit
+// status first, cmd.Wait() fails, so the status collected by the reaper is reported instead. cmd.ProcessState is not
+// set in that case.
+func startChild(cmd *exec.Cmd) (wait func() error, err error) {
+	// The reaper must not collect the child before it is registered.
+	childMu.Lock()
+	defer childMu.Unlock()
+
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+
+	pid := cmd.Process.Pid
+	status := make(chan syscall.WaitStatus, 1)
+	children[pid] = status
+
+	return func() error {
+		err := cmd.Wait()
+
+		childMu.Lock()
+		delete(children, pid)
+		childMu.Unlock()
+
+		if !errors.Is(err, syscall.ECHILD) {
+			return err
+		}
+
+		// The reaper sent the status before releasing childMu.
+		select {
+		case ws := <-status:
+			return waitStatusErr(ws)
+		default:
+			return err
+		}
+	}, nil
+}
+
+// Describes ws the way exec.ExitError does, or returns nil if the child exited successfully.
+func waitStatusErr(ws syscall.WaitStatus) error {
+	switch {
+	case ws.Signaled():
+		return fmt.Errorf("signal: %v", ws.Signal())
+	case ws.ExitStatus() != 0:
+		return fmt.Errorf("exit status %d", ws.ExitStatus())
+	default:
+		return nil
+	}
+}
+
+func signalGroup(pgid int, sig os.Signal) {
+	if s, ok := sig.(syscall.Signal); ok {
+		syscall.Kill(-pgid, s)
+	}
+}
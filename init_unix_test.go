@@ -0,0 +1,81 @@
+//go:build unix && !aix
+
+package graceful
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// Waits until pid no longer exists. A zombie can still be signaled, so the pid only disappears once its exit status
+// has been collected.
+func awaitReaped(t *testing.T, pid int) {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if err := syscall.Kill(pid, 0); errors.Is(err, syscall.ESRCH) {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatal("child was not reaped")
+}
+
+func TestReaperCollectsZombies(t *testing.T) {
+	stop := startReaper()
+	defer stop()
+
+	path := lookPath(t, "true")
+	p, err := os.StartProcess(path, []string{path}, &os.ProcAttr{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	awaitReaped(t, p.Pid)
+}
+
+func TestReaperHandsOverTrackedChildren(t *testing.T) {
+	stop := startReaper()
+	defer stop()
+
+	sh := lookPath(t, "sh")
+	for _, tt := range []struct {
+		script  string
+		wantErr string
+	}{
+		{"exit 0", ""},
+		{"exit 3", "exit status 3"},
+	} {
+		cmd := exec.Command(sh, "-c", tt.script)
+		wait, err := startChild(cmd)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Make sure the reaper collects the status before the waiter does.
+		awaitReaped(t, cmd.Process.Pid)
+
+		err = wait()
+		if (err == nil && tt.wantErr != "") || (err != nil && err.Error() != tt.wantErr) {
+			t.Fatalf("%q: expected %q, got %v", tt.script, tt.wantErr, err)
+		}
+	}
+}
+
+func TestInitModeStarts(t *testing.T) {
+	r := New()
+	r.Shutdown(nil)
+
+	er := r.Start([]Func{func(ctx context.Context) error {
+		return exec.Command(lookPath(t, "true")).Start()
+	}}, nil, WithInitMode())
+	if er.ErrStartup != nil || er.ErrsShutdown != nil {
+		t.Fatalf("expected a clean exit, got %v", er)
+	}
+}
@@ -0,0 +1,124 @@
+package graceful
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sync"
+)
+
+// Wraps fns so their execution is reported to the configured logger, tracer, event subscribers, and step timings.
+func (c *config) instrument(phase string, fns []Func) []Func {
+	wrapped, _ := c.instrumentSlots(phase, fns)
+	return wrapped
+}
+
+// Same as instrument(), but also returns the slot each function's Named() name is reported to, so functions which have
+// not returned can be identified.
+func (c *config) instrumentSlots(phase string, fns []Func) ([]Func, []*funcNameSlot) {
+	fns = c.injectChaos(phase, fns)
+
+	wrapped := make([]Func, len(fns))
+	slots := make([]*funcNameSlot, len(fns))
+	for i, fn := range fns {
+		slot := &funcNameSlot{}
+		slots[i] = slot
+		wrapped[i] = func(ctx context.Context) error {
+			ctx = context.WithValue(ctx, funcNameKey{}, slot)
+
+			ctx, span := c.startSpan(ctx, fmt.Sprintf("%s[%d]", phase, i))
+			c.log(slog.LevelDebug, phase+" function started", slog.Int("index", i))
+
+			start := c.now()
+			err := fn(ctx)
+			duration := c.since(start)
+
+			attrs := []any{slog.Int("index", i), slog.Duration("duration", duration)}
+			if name := slot.get(); name != "" {
+				attrs = append(attrs, slog.String("name", name))
+			}
+
+			if err != nil && isBestEffort(err) {
+				c.log(slog.LevelWarn, phase+" function failed (best effort)", append(attrs, slog.Any("err", err))...)
+			} else if err != nil {
+				c.log(slog.LevelError, phase+" function failed", append(attrs, slog.Any("err", err))...)
+			} else {
+				c.log(slog.LevelInfo, phase+" function completed", attrs...)
+			}
+
+			c.endSpan(span, slot.get(), err)
+			c.steps.record(StepTiming{Phase: phase, Index: i, Name: slot.get(), Start: start, Duration: duration, Err: err})
+
+			if c.emit != nil {
+				et := EventShutdownFuncCompleted
+				if phase == "startup" {
+					et = EventStartupFuncCompleted
+				}
+				c.emit(Event{Type: et, Phase: phase, Index: i, Name: slot.get(), Duration: duration, Err: err})
+			}
+
+			switch {
+			case err == nil:
+				return nil
+			case phase == "startup":
+				err = &StartupError{Step: i, Name: slot.get(), Err: err, Duration: duration}
+			default:
+				err = &ShutdownError{Phase: phase, Index: i, Name: slot.get(), Err: err, Duration: duration}
+			}
+			c.reportErr(Phase(phase), err)
+			return err
+		}
+	}
+
+	return wrapped, slots
+}
+
+// Collects StepTimings until the lifecycle returns. Functions abandoned after a timeout may return later, so steps
+// recorded after close() are discarded rather than racing with the caller reading ExitReason.
+type stepRecorder struct {
+	mu     sync.Mutex
+	steps  []StepTiming
+	closed bool
+	divert func(StepTiming)
+}
+
+// Safe to call on a nil recorder.
+func (sr *stepRecorder) record(st StepTiming) {
+	if sr == nil {
+		return
+	}
+
+	sr.mu.Lock()
+	defer sr.mu.Unlock()
+
+	if sr.divert != nil {
+		sr.divert(st)
+	} else if !sr.closed {
+		sr.steps = append(sr.steps, st)
+	}
+}
+
+// Sends steps recorded from now on to fn instead, ie to report functions which complete after the shutdown timeout.
+func (sr *stepRecorder) redirect(fn func(StepTiming)) {
+	sr.mu.Lock()
+	defer sr.mu.Unlock()
+
+	sr.divert = fn
+}
+
+// Returns a copy of the steps recorded so far.
+func (sr *stepRecorder) snapshot() []StepTiming {
+	sr.mu.Lock()
+	defer sr.mu.Unlock()
+
+	return slices.Clone(sr.steps)
+}
+
+func (sr *stepRecorder) close() []StepTiming {
+	sr.mu.Lock()
+	defer sr.mu.Unlock()
+
+	sr.closed = true
+	return sr.steps
+}
@@ -0,0 +1,211 @@
+package graceful
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Implements json.Marshaler using the printable form, plus the signal number so the signal can be restored. Children
+// are marshaled the same way.
+func (er *ExitReason) MarshalJSON() ([]byte, error) {
+	return json.Marshal(er.toJSON())
+}
+
+func (er *ExitReason) toJSON() *exitReasonJSON {
+	erj := &exitReasonJSON{
+		ExitReasonPrintable: er.ToPrintable(),
+		OsSignalNum:         signalNum(er),
+	}
+
+	for name, child := range er.Children {
+		if erj.Children == nil {
+			erj.Children = map[string]*exitReasonJSON{}
+		}
+		erj.Children[name] = child.toJSON()
+	}
+
+	return erj
+}
+
+// Implements json.Unmarshaler, restoring a struct marshaled by MarshalJSON() or MarshalStr().
+//
+// Errors are restored from their messages. The sentinel errors of this package and of the context package are
+// restored as themselves, so errors.Is() keeps working across a round trip. Other error types are not preserved.
+func (er *ExitReason) UnmarshalJSON(b []byte) error {
+	erj := &exitReasonJSON{}
+	if err := json.Unmarshal(b, erj); err != nil {
+		return err
+	}
+
+	restored, err := erj.restore()
+	if err != nil {
+		return err
+	}
+
+	*er = *restored
+	return nil
+}
+
+func (erj *exitReasonJSON) restore() (*ExitReason, error) {
+	if erj.ExitReasonPrintable == nil {
+		erj.ExitReasonPrintable = &ExitReasonPrintable{}
+	}
+	erp := erj.ExitReasonPrintable
+
+	restored := &ExitReason{
+		ErrStartup:   parseErr(erp.ErrStartup),
+		ErrRuntime:   parseErr(erp.ErrRuntime),
+		ErrsRuntime:  parseErrs(erp.ErrsRuntime),
+		ErrContext:   parseErr(erp.ErrContext),
+		Reason:       erp.Reason,
+		ErrsShutdown: parseErrs(erp.ErrsShutdown),
+		Restarts:     erp.Restarts,
+
+		WarningsStartup:  parseErrs(erp.WarningsStartup),
+		WarningsShutdown: parseErrs(erp.WarningsShutdown),
+	}
+
+	if erj.OsSignalNum != 0 {
+		restored.OsSignal = signalFromNumber(erj.OsSignalNum)
+	}
+	if restored.OsSignal == nil && erp.OsSignal != "" {
+		restored.OsSignal = namedSignal(erp.OsSignal)
+	}
+
+	if erp.GoroutineDump != "" {
+		restored.GoroutineDump = []byte(erp.GoroutineDump)
+	}
+
+	if erp.Timing.ShutdownDelay != "" {
+		d, err := time.ParseDuration(erp.Timing.ShutdownDelay)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse shutdownDelay: %w", err)
+		}
+		restored.Timing.ShutdownDelay = d
+	}
+
+	if erp.Timing.Inhibited != "" {
+		d, err := time.ParseDuration(erp.Timing.Inhibited)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse inhibited: %w", err)
+		}
+		restored.Timing.Inhibited = d
+	}
+
+	for name, child := range erj.Children {
+		cer, err := child.restore()
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse child %q: %w", name, err)
+		}
+		if restored.Children == nil {
+			restored.Children = map[string]*ExitReason{}
+		}
+		restored.Children[name] = cer
+	}
+
+	for _, stp := range erp.Timing.Steps {
+		st := StepTiming{Phase: stp.Phase, Index: stp.Index, Name: stp.Name, Err: parseErr(stp.Err)}
+
+		var err error
+		if st.Start, err = time.Parse(time.RFC3339Nano, stp.Start); err != nil {
+			return nil, fmt.Errorf("failed to parse step start: %w", err)
+		}
+		if st.Duration, err = time.ParseDuration(stp.Duration); err != nil {
+			return nil, fmt.Errorf("failed to parse step duration: %w", err)
+		}
+
+		restored.Timing.Steps = append(restored.Timing.Steps, st)
+	}
+
+	return restored, nil
+}
+
+type exitReasonJSON struct {
+	*ExitReasonPrintable
+	OsSignalNum int `json:"osSignalNum,omitempty"`
+
+	// Shadows ExitReasonPrintable.Children, so the children's signal numbers are kept too.
+	Children map[string]*exitReasonJSON `json:"children,omitempty"`
+}
+
+// A signal restored from its name only, when its number is unknown.
+type namedSignal string
+
+func (ns namedSignal) String() string { return string(ns) }
+func (ns namedSignal) Signal()        {}
+
+func signalNum(er *ExitReason) int {
+	n, _ := signalNumber(er.OsSignal)
+	return n
+}
+
+var knownErrs = []error{
+	ErrStartupTimeout,
+	ErrShutdownTimeout,
+	ErrShutdownFuncTimeout,
+	ErrShutdownKilled,
+	ErrShutdownForced,
+	ErrNoStartupContext,
+	context.Canceled,
+	context.DeadlineExceeded,
+}
+
+func parseErr(msg string) error {
+	if msg == "" {
+		return nil
+	}
+
+	for _, known := range knownErrs {
+		if known.Error() == msg {
+			return known
+		}
+	}
+
+	// Restore *StartupError and *ShutdownError so the failed function's position survives a round trip. Its duration is
+	// not part of the message.
+	var ste StartupError
+	if n, _ := fmt.Sscanf(msg, "startup[%d]", &ste.Step); n == 1 {
+		if ste.Name, ste.Err = parsePosition(msg); ste.Err != nil {
+			return &ste
+		}
+	}
+
+	var se ShutdownError
+	if n, _ := fmt.Sscanf(msg, "shutdown[%d]", &se.Index); n == 1 {
+		se.Phase = "shutdown"
+	} else if n, _ := fmt.Sscanf(msg, "rollback[%d]", &se.Index); n == 1 {
+		se.Phase = "rollback"
+	}
+	if se.Phase != "" {
+		if se.Name, se.Err = parsePosition(msg); se.Err != nil {
+			return &se
+		}
+	}
+
+	return errors.New(msg)
+}
+
+// Parses the name (if any) and error following the prefix written by position().
+func parsePosition(msg string) (name string, err error) {
+	_, rest, ok := strings.Cut(msg, "]")
+	if !ok {
+		return "", nil
+	}
+
+	if name, rest, ok = strings.Cut(rest, ": "); !ok {
+		return "", nil
+	}
+	return strings.TrimPrefix(name, " "), parseErr(rest)
+}
+
+func parseErrs(msgs []string) []error {
+	var errs []error
+	for _, msg := range msgs {
+		errs = append(errs, parseErr(msg))
+	}
+	return errs
+}
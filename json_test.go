@@ -0,0 +1,90 @@
+package graceful
+
+import (
+	"encoding/json"
+	"errors"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestExitReasonJSONRoundTrip(t *testing.T) {
+	start := time.Date(2024, 1, 2, 3, 4, 5, 6, time.UTC)
+	er := &ExitReason{
+		OsSignal:     syscall.SIGTERM,
+		ErrStartup:   &StartupError{Step: 2, Name: "connect", Err: ErrStartupTimeout},
+		ErrRuntime:   errors.New("lost connection"),
+		Reason:       "stopped",
+		ErrsShutdown: []error{&ShutdownError{Phase: "shutdown", Index: 1, Name: "db", Err: ErrShutdownFuncTimeout}, ErrShutdownTimeout},
+		Restarts:     2,
+		Timing: Timing{
+			ShutdownDelay: time.Second,
+			Inhibited:     time.Millisecond,
+			Steps:         []StepTiming{{Phase: "shutdown", Index: 1, Name: "db", Start: start, Duration: time.Millisecond, Err: ErrShutdownFuncTimeout}},
+		},
+		WarningsShutdown: []error{errors.New("cache flush failed")},
+	}
+
+	b, err := json.Marshal(er)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got ExitReason
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatal(err)
+	}
+
+	if got.OsSignal != syscall.SIGTERM {
+		t.Errorf("signal: expected %v, got %#v", syscall.SIGTERM, got.OsSignal)
+	}
+	var ste *StartupError
+	if !errors.As(got.ErrStartup, &ste) || ste.Step != 2 || ste.Name != "connect" || !errors.Is(got.ErrStartup, ErrStartupTimeout) {
+		t.Errorf("startup error not restored: %#v", got.ErrStartup)
+	}
+	if got.ErrRuntime.Error() != "lost connection" || got.Reason != "stopped" || got.Restarts != 2 {
+		t.Errorf("unexpected fields: %v, %q, %d", got.ErrRuntime, got.Reason, got.Restarts)
+	}
+	var se *ShutdownError
+	if len(got.ErrsShutdown) != 2 || !errors.As(got.ErrsShutdown[0], &se) || se.Index != 1 || se.Name != "db" ||
+		!errors.Is(got.ErrsShutdown[0], ErrShutdownFuncTimeout) || got.ErrsShutdown[1] != ErrShutdownTimeout {
+		t.Errorf("shutdown errors not restored: %#v", got.ErrsShutdown)
+	}
+	if got.Timing.ShutdownDelay != time.Second || got.Timing.Inhibited != time.Millisecond {
+		t.Errorf("timing not restored: %+v", got.Timing)
+	}
+	if len(got.Timing.Steps) != 1 || !got.Timing.Steps[0].Start.Equal(start) || got.Timing.Steps[0].Name != "db" ||
+		got.Timing.Steps[0].Err != ErrShutdownFuncTimeout {
+		t.Errorf("steps not restored: %+v", got.Timing.Steps)
+	}
+	if len(got.WarningsShutdown) != 1 || got.WarningsShutdown[0].Error() != "cache flush failed" {
+		t.Errorf("warnings not restored: %v", got.WarningsShutdown)
+	}
+	if got.ExitCode() != er.ExitCode() {
+		t.Errorf("exit code: expected %d, got %d", er.ExitCode(), got.ExitCode())
+	}
+}
+
+func TestExitReasonMarshalStrRoundTrip(t *testing.T) {
+	er := &ExitReason{OsSignal: syscall.SIGTERM, ErrContext: errors.New("canceled by parent")}
+
+	s, err := er.MarshalStrErr()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s != er.MarshalStr() {
+		t.Errorf("MarshalStr() and MarshalStrErr() disagree:\n%s\n%s", er.MarshalStr(), s)
+	}
+
+	// The printable form has no signal number, so the signal is restored by name.
+	var got ExitReason
+	if err := json.Unmarshal([]byte(s), &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.OsSignal == nil || got.OsSignal.String() != syscall.SIGTERM.String() {
+		t.Errorf("signal: expected %v, got %v", syscall.SIGTERM, got.OsSignal)
+	}
+	if got.ErrContext == nil || got.ErrContext.Error() != "canceled by parent" {
+		t.Errorf("context error: got %v", got.ErrContext)
+	}
+}
@@ -0,0 +1,32 @@
+package graceful
+
+import (
+	"os"
+	"syscall"
+	"time"
+)
+
+// Applies best practices for running as a Kubernetes pod whose terminationGracePeriodSeconds is gracePeriod:
+//
+//   - Only SIGTERM triggers shutdown, since that is what the kubelet sends.
+//   - A shutdown delay of a sixth of gracePeriod (at most 5s) keeps serving while endpoints are removed from Services
+//     and load balancers. Readiness (see HealthHandler()) is off during the delay.
+//   - The shutdown timeout is what remains of gracePeriod after the delay and a safety buffer of the same length, so
+//     shutdown completes before the kubelet sends SIGKILL.
+//
+// For the default grace period of 30s, this is a 5s delay followed by a 20s shutdown timeout. Options after this one
+// override it.
+func WithKubernetesDefaults(gracePeriod time.Duration) Option {
+	return func(config *config) error {
+		if gracePeriod < time.Second {
+			return invalidOption("termination grace period must be at least 1s")
+		}
+
+		delay := min(gracePeriod/6, 5*time.Second)
+		return config.applyDefaults(
+			WithSignalsReplace([]os.Signal{syscall.SIGTERM}),
+			WithShutdownDelay(delay),
+			WithShutdownTimeout(gracePeriod-2*delay),
+		)
+	}
+}
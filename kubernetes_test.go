@@ -0,0 +1,50 @@
+package graceful
+
+import (
+	"os"
+	"slices"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestKubernetesDefaults(t *testing.T) {
+	tests := []struct {
+		gracePeriod time.Duration
+		delay       time.Duration
+		timeout     time.Duration
+	}{
+		{30 * time.Second, 5 * time.Second, 20 * time.Second},
+		{60 * time.Second, 5 * time.Second, 50 * time.Second},
+		{12 * time.Second, 2 * time.Second, 8 * time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.gracePeriod.String(), func(t *testing.T) {
+			config := &config{}
+			if err := parseOptions(config, []Option{WithKubernetesDefaults(tt.gracePeriod)}); err != nil {
+				t.Fatal(err)
+			}
+			if config.shutdownDelay != tt.delay || config.shutdownTimeout != tt.timeout {
+				t.Fatalf("expected a %v delay and %v timeout, got %v and %v", tt.delay, tt.timeout,
+					config.shutdownDelay, config.shutdownTimeout)
+			}
+			if !slices.Equal(config.signals, []os.Signal{syscall.SIGTERM}) {
+				t.Fatalf("expected only SIGTERM, got %v", config.signals)
+			}
+		})
+	}
+
+	if err := parseOptions(&config{}, []Option{WithKubernetesDefaults(time.Millisecond)}); err == nil {
+		t.Fatal("expected a grace period under 1s to be rejected")
+	}
+
+	// Later options override the bundle.
+	config := &config{}
+	if err := parseOptions(config, []Option{WithKubernetesDefaults(30 * time.Second), WithShutdownDelay(time.Second)}); err != nil {
+		t.Fatal(err)
+	}
+	if config.shutdownDelay != time.Second {
+		t.Fatalf("expected the later delay to win, got %v", config.shutdownDelay)
+	}
+}
@@ -0,0 +1,74 @@
+package graceful
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// Returned by the run function of WhenLeader() when leadership is lost while the application is running.
+var ErrLeadershipLost = errors.New("leadership lost")
+
+// Acquires and releases leadership through a lease, ie a Kubernetes Lease, an etcd session, or a database lock.
+type Elector interface {
+	// Blocks until leadership is acquired, or returns ctx's error. The returned context is canceled when leadership is
+	// lost.
+	Campaign(ctx context.Context) (leaderCtx context.Context, err error)
+
+	// Releases leadership so another instance can acquire it without waiting for the lease to expire.
+	Resign(ctx context.Context) error
+}
+
+// Returns a run function (see WithRun()) which runs fns concurrently, but only once this instance is the leader.
+// Useful for singleton workers, such as schedulers, which must not run on every replica.
+//
+// fns receive a context which is canceled when shutdown begins or leadership is lost. When shutdown begins, the run
+// function waits for them to return and then resigns. If leadership is lost, it waits for them and returns
+// ErrLeadershipLost, which triggers shutdown. See WhenLeaderReelect() to campaign again instead. If one of fns fails,
+// the others are canceled, leadership is resigned, and the error is returned.
+func WhenLeader(e Elector, fns ...Func) Func {
+	return whenLeader(e, false, fns)
+}
+
+// Same as WhenLeader(), but if leadership is lost, fns are stopped and the run function campaigns again instead of
+// triggering shutdown.
+func WhenLeaderReelect(e Elector, fns ...Func) Func {
+	return whenLeader(e, true, fns)
+}
+
+func whenLeader(e Elector, reelect bool, fns []Func) Func {
+	run := MultiFailFast(fns...)
+
+	return func(ctx context.Context) error {
+		for {
+			leaderCtx, err := e.Campaign(ctx)
+			if err != nil {
+				if ctx.Err() != nil {
+					return nil
+				}
+				return fmt.Errorf("failed to campaign for leadership: %w", err)
+			}
+
+			runCtx, cancel := context.WithCancel(ctx)
+			stop := context.AfterFunc(leaderCtx, cancel)
+			err = run(runCtx)
+			stop()
+			cancel()
+
+			// Errors of fns caused by losing leadership are a consequence, not the cause.
+			if ctx.Err() == nil && leaderCtx.Err() != nil {
+				if reelect {
+					continue
+				}
+				return ErrLeadershipLost
+			}
+
+			// Shutdown began, one of fns failed, or they all returned. Resign even though shutdown may have begun, so
+			// another instance can take over immediately.
+			if rerr := e.Resign(context.WithoutCancel(ctx)); rerr != nil {
+				err = errors.Join(err, fmt.Errorf("failed to resign leadership: %w", rerr))
+			}
+			return err
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package graceful
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+)
+
+// Grants leadership on every campaign. Leadership is lost by calling lose().
+type fakeElector struct {
+	campaigns atomic.Int32
+	resigned  atomic.Bool
+	lose      context.CancelFunc
+}
+
+func (e *fakeElector) Campaign(ctx context.Context) (context.Context, error) {
+	e.campaigns.Add(1)
+	leaderCtx, lose := context.WithCancel(context.Background())
+	e.lose = lose
+	return leaderCtx, nil
+}
+
+func (e *fakeElector) Resign(ctx context.Context) error {
+	e.resigned.Store(true)
+	return nil
+}
+
+func TestWhenLeader(t *testing.T) {
+	e := &fakeElector{}
+	ctx, cancel := context.WithCancel(context.Background())
+
+	ran := false
+	fn := func(ctx context.Context) error {
+		ran = true
+		cancel()
+		<-ctx.Done()
+		return nil
+	}
+
+	if err := WhenLeader(e, fn)(ctx); err != nil || !ran || !e.resigned.Load() {
+		t.Fatalf("expected fn to run and leadership to be resigned, got ran=%v resigned=%v (%v)", ran, e.resigned.Load(), err)
+	}
+}
+
+func TestWhenLeaderLost(t *testing.T) {
+	e := &fakeElector{}
+
+	fn := func(ctx context.Context) error {
+		e.lose()
+		<-ctx.Done()
+		return ctx.Err()
+	}
+
+	if err := WhenLeader(e, fn)(context.Background()); err != ErrLeadershipLost {
+		t.Fatalf("expected ErrLeadershipLost, got %v", err)
+	}
+	if e.resigned.Load() {
+		t.Fatalf("expected no resignation after leadership was lost")
+	}
+}
+
+func TestWhenLeaderReelect(t *testing.T) {
+	e := &fakeElector{}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	fn := func(ctx context.Context) error {
+		if e.campaigns.Load() == 1 {
+			e.lose()
+		} else {
+			cancel()
+		}
+		<-ctx.Done()
+		return nil
+	}
+
+	if err := WhenLeaderReelect(e, fn)(ctx); err != nil || e.campaigns.Load() != 2 {
+		t.Fatalf("expected a second campaign after leadership was lost, got %d (%v)", e.campaigns.Load(), err)
+	}
+}
+
+func TestWhenLeaderFailure(t *testing.T) {
+	errBoom := errors.New("boom")
+	e := &fakeElector{}
+
+	fail := func(ctx context.Context) error { return errBoom }
+	if err := WhenLeader(e, fail)(context.Background()); !errors.Is(err, errBoom) || !e.resigned.Load() {
+		t.Fatalf("expected the error and a resignation, got resigned=%v (%v)", e.resigned.Load(), err)
+	}
+}
+
+// Blocks every campaign until ctx is done.
+type blockedElector struct{}
+
+func (blockedElector) Campaign(ctx context.Context) (context.Context, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func (blockedElector) Resign(ctx context.Context) error { return nil }
+
+func TestWhenLeaderShutdownWhileCampaigning(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	fn := func(ctx context.Context) error {
+		t.Error("expected fn not to run without leadership")
+		return nil
+	}
+	if err := WhenLeader(blockedElector{}, fn)(ctx); err != nil {
+		t.Fatalf("expected nil when shutdown began while campaigning, got %v", err)
+	}
+}
@@ -0,0 +1,13 @@
+package graceful
+
+import (
+	"context"
+	"log/slog"
+)
+
+// Reports a lifecycle event to the configured logger, if any.
+func (c *config) log(level slog.Level, msg string, attrs ...any) {
+	if c.logger != nil {
+		c.logger.Log(context.Background(), level, msg, attrs...)
+	}
+}
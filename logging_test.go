@@ -0,0 +1,35 @@
+package graceful
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestWithLogger(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	ok := func(ctx context.Context) error { return nil }
+	fail := func(ctx context.Context) error { return errors.New("boom") }
+
+	r := New()
+	r.Shutdown(nil)
+	r.Start([]Func{ok}, []Func{fail}, WithLogger(logger))
+
+	for _, msg := range []string{
+		`msg="startup function started" index=0`,
+		`msg="startup function completed" index=0`,
+		`msg="startup completed"`,
+		`msg="shutdown function failed" index=0`,
+		`err=boom`,
+		`msg="shutdown completed" errors=1`,
+	} {
+		if !strings.Contains(buf.String(), msg) {
+			t.Errorf("expected the log to contain %s, got:\n%s", msg, buf.String())
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package graceful
+
+import (
+	"context"
+	"log/slog"
+	"os"
+)
+
+// Reports whether the default Runner is in maintenance mode. See WithMaintenanceSignal().
+func Maintenance() bool {
+	return defaultRunner.Maintenance()
+}
+
+// Same as the package-level Maintenance(), but scoped to this Runner.
+func (r *Runner) Maintenance() bool {
+	return r.maintenance.Load()
+}
+
+// When sig is received during the run phase, the application enters maintenance mode without shutting down: Maintenance()
+// becomes true and enter is run (ie to drain caches and pause consumers). Receiving sig again leaves maintenance mode:
+// Maintenance() becomes false and exit is run. Either function may be nil. Errors are reported to the logger and event
+// subscribers rather than triggering shutdown. sig is removed from the shutdown signals. Default: none.
+func WithMaintenanceSignal(sig os.Signal, enter, exit Func) Option {
+	return func(config *config) error {
+		if sig == nil {
+			return invalidOption("maintenance signal must not be nil")
+		}
+		config.maintenanceSig = sig
+		config.maintenanceEnter = enter
+		config.maintenanceExit = exit
+		return nil
+	}
+}
+
+// Toggles maintenance mode whenever the maintenance signal is received, until ctx is done. The signal is trapped until
+// the returned function is called.
+func (r *Runner) watchMaintenance(ctx context.Context, config *config) (stop func()) {
+	r.maintenance.Store(false)
+	if config.maintenanceSig == nil {
+		return nop
+	}
+
+	mSig := make(chan os.Signal, 1)
+	r.notify(mSig, config.maintenanceSig)
+
+	go func() {
+		for {
+			select {
+			case sig := <-mSig:
+				r.toggleMaintenance(ctx, config, sig)
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return func() {
+		r.stopNotify(mSig)
+	}
+}
+
+func (r *Runner) toggleMaintenance(ctx context.Context, config *config, sig os.Signal) {
+	on := !r.maintenance.Load()
+	r.maintenance.Store(on)
+
+	fn, et, msg := config.maintenanceExit, EventMaintenanceEnded, "maintenance mode ended"
+	if on {
+		fn, et, msg = config.maintenanceEnter, EventMaintenanceBegan, "maintenance mode began"
+	}
+
+	start := config.now()
+	var err error
+	if fn != nil {
+		err = config.recoverPanics([]Func{fn})[0](ctx)
+	}
+	duration := config.since(start)
+
+	if err != nil {
+		config.log(slog.LevelError, msg+" with errors", slog.String("signal", sig.String()), slog.Duration("duration", duration), slog.Any("err", err))
+	} else {
+		config.log(slog.LevelInfo, msg, slog.String("signal", sig.String()), slog.Duration("duration", duration))
+	}
+	r.emit(Event{Type: et, Signal: sig, Duration: duration, Err: err})
+}
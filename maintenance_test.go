@@ -0,0 +1,83 @@
+package graceful
+
+import (
+	"context"
+	"errors"
+	"os"
+	"slices"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestMaintenanceSignal(t *testing.T) {
+	errBoom := errors.New("boom")
+	maint := testSignal("maint")
+
+	entered := false
+	enter := func(ctx context.Context) error {
+		entered = true
+		return nil
+	}
+	exit := func(ctx context.Context) error { return errBoom }
+
+	r := New()
+	events := make(chan Event, 64)
+	r.Subscribe(events)
+	defer r.Unsubscribe(events)
+
+	await := func(et EventType) (Event, error) {
+		for {
+			select {
+			case e := <-events:
+				if e.Type == et {
+					return e, nil
+				}
+			case <-time.After(time.Second):
+				return Event{}, errors.New(et.String() + " was not emitted")
+			}
+		}
+	}
+
+	var inMaintenance bool
+	var ended Event
+	run := func(ctx context.Context) error {
+		r.Signal(maint)
+		if _, err := await(EventMaintenanceBegan); err != nil {
+			return err
+		}
+		inMaintenance = r.Maintenance()
+
+		r.Signal(maint)
+		var err error
+		ended, err = await(EventMaintenanceEnded)
+		return err
+	}
+
+	er := r.Start(nil, nil, WithMaintenanceSignal(maint, enter, exit), WithRun(run))
+
+	if er.ErrRuntime != nil || er.OsSignal != nil {
+		t.Fatalf("expected the maintenance signal not to trigger shutdown, got %v", er)
+	}
+	if !entered || !inMaintenance || r.Maintenance() {
+		t.Fatalf("expected maintenance mode to be entered and left, got entered=%v in=%v", entered, inMaintenance)
+	}
+	if ended.Signal != maint || !errors.Is(ended.Err, errBoom) {
+		t.Fatalf("expected the failed exit function to be reported, got %+v", ended)
+	}
+}
+
+func TestMaintenanceSignalOptions(t *testing.T) {
+	c := &config{signals: []os.Signal{os.Interrupt, syscall.SIGTERM}}
+	if err := parseOptions(c, []Option{WithMaintenanceSignal(syscall.SIGTERM, nil, nil)}); err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(c.signals, []os.Signal{os.Interrupt}) {
+		t.Fatalf("expected the maintenance signal removed from the shutdown signals, got %v", c.signals)
+	}
+
+	opts := []Option{WithReloadSignal(syscall.SIGTERM), WithMaintenanceSignal(syscall.SIGTERM, nil, nil)}
+	if err := parseOptions(&config{}, opts); !errors.Is(err, ErrInvalidOption) {
+		t.Fatalf("expected a reload signal to be rejected as the maintenance signal, got %v", err)
+	}
+}
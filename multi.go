@@ -1,39 +1,179 @@
 package graceful
 
-import "context"
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"strings"
+	"time"
+)
 
 // Provides convenience wrapper to run multiple Funcs concurrently by Start().
 //
 // Useful for speeding up startup or shutdown, where each function does not depend on any preceding step.
 // For example, you may want to concurrently initialize connections to a db, cache, config service, etc at the same time.
 //
-// Order is not guaranteed for these functions. All provided functions must return before the returned function returns.
+// Order is not guaranteed for these functions. All provided functions must return before the returned function returns,
+// unless the context is done first. In that case, the functions which have not returned are detached and reported in
+// an *AbandonedError.
 //
-// Only the first error (if any) received will be reported.
+// Every error received is reported, combined with errors.Join(). Errors from functions not wrapped with Named() are
+// attributed to "multi[i]", where i is the function's index, so FuncName() identifies which function failed.
 func Multi(fns ...Func) Func {
+	return MultiN(len(fns), fns...)
+}
+
+// Same as Multi(), but runs at most limit functions at a time. A limit less than 1 is treated as 1.
+//
+// Useful when there are many functions which would otherwise overwhelm a downstream service, such as cache warm-ups.
+// Functions which have not started by the time the context is done are never started.
+func MultiN(limit int, fns ...Func) Func {
+	return multi(max(limit, 1), 0, 0, fns)
+}
+
+// Same as Multi(), but starts each function gap after the previous one, plus a random duration of up to jitter (which
+// may be 0). Useful when starting many functions at once would trip a rate limit, such as a broker's connection rate.
+// The gaps are measured by the Clock given to WithClock(), if any. Functions which have not started by the time the
+// context is done are never started.
+func MultiStaggered(gap time.Duration, jitter time.Duration, fns ...Func) Func {
+	return multi(max(len(fns), 1), gap, jitter, fns)
+}
+
+func multi(limit int, gap time.Duration, jitter time.Duration, fns []Func) Func {
 	return func(ctx context.Context) error {
-		errCh := make(chan error, len(fns))
+		results := make(chan multiResult, len(fns))
+		sem := make(chan struct{}, limit)
 
-		// Run functions concurrently
-		for _, fn := range fns {
-			go func(f Func) {
-				errCh <- f(ctx)
-			}(fn)
+		// Each function reports its name (if Named) to its own slot so abandoned functions can be identified.
+		slots := make([]*funcNameSlot, len(fns))
+		for i := range slots {
+			slots[i] = &funcNameSlot{}
 		}
 
-		// Read their errors and store the first (if any)
-		var err error
+		// Run functions concurrently, at most limit at a time and gap apart
+		go func() {
+			for i, fn := range fns {
+				if i > 0 && gap+jitter > 0 {
+					d := gap
+					if jitter > 0 {
+						d += rand.N(jitter)
+					}
+
+					select {
+					case <-afterFrom(ctx, d):
+					case <-ctx.Done():
+						return
+					}
+				}
+
+				select {
+				case sem <- struct{}{}:
+				case <-ctx.Done():
+					return
+				}
+
+				go func(i int, f Func) {
+					defer func() { <-sem }()
+					err := f(context.WithValue(ctx, funcNameKey{}, slots[i]))
+					results <- multiResult{index: i, err: attribute("multi", i, err)}
+				}(i, fn)
+			}
+		}()
+
+		// Read their errors
+		var errs []error
+		returned := make([]bool, len(fns))
 		for range len(fns) {
 			select {
 			case <-ctx.Done():
-				return ctx.Err()
-			case e := <-errCh:
-				if err == nil && e != nil {
-					err = e
+				ae := &AbandonedError{Err: ctx.Err()}
+				for i, ok := range returned {
+					if !ok {
+						ae.Names = append(ae.Names, slotName("multi", i, slots[i]))
+					}
 				}
+				return errors.Join(append(errs, ae)...)
+			case res := <-results:
+				returned[res.index] = true
+				if res.err != nil {
+					errs = append(errs, res.err)
+				}
+			}
+		}
+
+		return errors.Join(errs...)
+	}
+}
+
+// Reported by Multi(), MultiN(), and MultiStaggered() when the context is done before all functions return.
+type AbandonedError struct {
+	// Names of the functions which had not returned, as given by Named() or "multi[i]" otherwise.
+	Names []string
+	Err   error
+}
+
+func (ae *AbandonedError) Error() string {
+	return fmt.Sprintf("abandoned %d function(s) [%s]: %v", len(ae.Names), strings.Join(ae.Names, ", "), ae.Err)
+}
+
+func (ae *AbandonedError) Unwrap() error {
+	return ae.Err
+}
+
+type multiResult struct {
+	index int
+	err   error
+}
+
+// Returns the name reported to slot by Named(), or "combinator[i]" otherwise.
+func slotName(combinator string, i int, slot *funcNameSlot) string {
+	if name := slot.get(); name != "" {
+		return name
+	}
+	return fmt.Sprintf("%s[%d]", combinator, i)
+}
+
+// Same as Multi(), but cancels the context passed to the remaining functions as soon as one returns a non-nil error,
+// similar to errgroup.WithContext().
+//
+// All provided functions must still return before the returned function returns. Only the first error is reported,
+// since the others are usually a consequence of the cancellation.
+func MultiFailFast(fns ...Func) Func {
+	return func(ctx context.Context) error {
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		errCh := make(chan error, len(fns))
+
+		for i, fn := range fns {
+			go func(i int, f Func) {
+				errCh <- attribute("multi", i, f(ctx))
+			}(i, fn)
+		}
+
+		var err error
+		for range len(fns) {
+			if e := <-errCh; e != nil && err == nil {
+				err = e
+				cancel()
 			}
 		}
 
 		return err
 	}
 }
+
+// Attributes err to the function at index i of a combinator, unless it is already attributed with Named().
+func attribute(combinator string, i int, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var fe *FuncError
+	if errors.As(err, &fe) {
+		return err
+	}
+
+	return &FuncError{Name: fmt.Sprintf("%s[%d]", combinator, i), Err: err}
+}
@@ -0,0 +1,183 @@
+package graceful
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestMultiJoinsAllErrors(t *testing.T) {
+	errA, errB := errors.New("a"), errors.New("b")
+	ok := func(ctx context.Context) error { return nil }
+	fail := func(err error) Func {
+		return func(ctx context.Context) error { return err }
+	}
+
+	err := Multi(fail(errA), ok, Named("db", fail(errB)))(context.Background())
+
+	if !errors.Is(err, errA) || !errors.Is(err, errB) {
+		t.Fatalf("expected both errors, got %v", err)
+	}
+
+	var names []string
+	for _, e := range err.(interface{ Unwrap() []error }).Unwrap() {
+		names = append(names, FuncName(e))
+	}
+	slices.Sort(names)
+	if !slices.Equal(names, []string{"db", "multi[0]"}) {
+		t.Fatalf("unexpected attribution: %v", names)
+	}
+}
+
+func TestMultiNoErrors(t *testing.T) {
+	ok := func(ctx context.Context) error { return nil }
+	if err := Multi(ok, ok)(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestMultiContextDone(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	block := make(chan struct{})
+	defer close(block)
+	err := Multi(func(ctx context.Context) error {
+		<-block
+		return nil
+	})(ctx)
+
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestMultiFailFast(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	canceled := make(chan bool, 1)
+	err := MultiFailFast(
+		func(ctx context.Context) error {
+			<-ctx.Done()
+			canceled <- true
+			return ctx.Err()
+		},
+		func(ctx context.Context) error { return errBoom },
+	)(context.Background())
+
+	if !<-canceled {
+		t.Fatal("expected the remaining function's context to be canceled")
+	}
+	if !errors.Is(err, errBoom) || errors.Is(err, context.Canceled) || FuncName(err) != "multi[1]" {
+		t.Fatalf("expected only the first error, got %v", err)
+	}
+}
+
+func TestMultiN(t *testing.T) {
+	for _, limit := range []int{0, 1, 3} {
+		var running, peak atomic.Int32
+		fn := func(ctx context.Context) error {
+			n := running.Add(1)
+			for {
+				p := peak.Load()
+				if n <= p || peak.CompareAndSwap(p, n) {
+					break
+				}
+			}
+			time.Sleep(5 * time.Millisecond)
+			running.Add(-1)
+			return nil
+		}
+
+		if err := MultiN(limit, fn, fn, fn, fn, fn, fn)(context.Background()); err != nil {
+			t.Fatalf("limit %d: unexpected error: %v", limit, err)
+		}
+		if want := int32(max(limit, 1)); peak.Load() != want {
+			t.Fatalf("limit %d: expected at most %d functions at a time, peak was %d", limit, want, peak.Load())
+		}
+	}
+}
+
+func TestMultiAbandoned(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	block := make(chan struct{})
+	defer close(block)
+	started := make(chan struct{}, 2)
+	hung := func(ctx context.Context) error {
+		started <- struct{}{}
+		<-block
+		return nil
+	}
+	go func() {
+		<-started
+		<-started
+		cancel()
+	}()
+
+	err := Multi(Named("cache", hung), hung)(ctx)
+
+	var ae *AbandonedError
+	if !errors.As(err, &ae) || !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected an AbandonedError, got %v", err)
+	}
+	slices.Sort(ae.Names)
+	if !slices.Equal(ae.Names, []string{"cache", "multi[1]"}) {
+		t.Fatalf("unexpected abandoned functions: %v", ae.Names)
+	}
+}
+
+func TestMultiStaggered(t *testing.T) {
+	clock := &tickClock{ticks: make(chan time.Time)}
+	ctx, cancel := context.WithCancel(context.WithValue(context.Background(), clockKey{}, Clock(clock)))
+	defer cancel()
+
+	started := make(chan int, 3)
+	fn := func(i int) Func {
+		return func(ctx context.Context) error {
+			started <- i
+			return nil
+		}
+	}
+	done := make(chan error)
+	go func() { done <- MultiStaggered(time.Minute, time.Second, fn(0), fn(1), fn(2))(ctx) }()
+
+	// The first function starts at once, each of the others only after a gap.
+	for i := range 3 {
+		if i > 0 {
+			select {
+			case <-started:
+				t.Fatalf("expected function %d to wait for the gap", i)
+			default:
+			}
+			clock.ticks <- time.Now()
+		}
+		if got := <-started; got != i {
+			t.Fatalf("expected function %d to start, got %d", i, got)
+		}
+	}
+	if err := <-done; err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestMultiStaggeredContextDone(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	var calls atomic.Int32
+	fn := func(ctx context.Context) error {
+		calls.Add(1)
+		cancel()
+		return nil
+	}
+
+	if err := MultiStaggered(time.Hour, 0, fn, fn, fn)(ctx); err != nil && !errors.Is(err, context.Canceled) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if calls.Load() != 1 {
+		t.Fatalf("expected functions not yet started to be skipped, got %d calls", calls.Load())
+	}
+}
@@ -0,0 +1,67 @@
+package graceful
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+)
+
+// Returned by functions wrapped with Named() to identify which function produced an error.
+type FuncError struct {
+	Name string
+	Err  error
+}
+
+func (fe *FuncError) Error() string {
+	return fe.Name + ": " + fe.Err.Error()
+}
+
+func (fe *FuncError) Unwrap() error {
+	return fe.Err
+}
+
+// Wraps fn so that any error it returns is attributed to name.
+//
+// The name is included in the error's message (and therefore ExitReason's printable/JSON forms) and can be retrieved
+// with FuncName().
+func Named(name string, fn Func) Func {
+	return func(ctx context.Context) error {
+		// Report the name to the instrumentation wrapping this function. Nested functions must not overwrite it.
+		if slot, ok := ctx.Value(funcNameKey{}).(*funcNameSlot); ok && slot != nil {
+			slot.set(name)
+			ctx = context.WithValue(ctx, funcNameKey{}, (*funcNameSlot)(nil))
+		}
+
+		if err := fn(ctx); err != nil {
+			return &FuncError{Name: name, Err: err}
+		}
+		return nil
+	}
+}
+
+// Returns the name of the function which produced err, or "" if err was not produced by a function wrapped with Named().
+func FuncName(err error) string {
+	var fe *FuncError
+	if errors.As(err, &fe) {
+		return fe.Name
+	}
+	return ""
+}
+
+type funcNameKey struct{}
+
+// Receives the name of the outermost Named() function run under an instrumented function. The first name set wins.
+type funcNameSlot struct {
+	name atomic.Pointer[string]
+}
+
+func (s *funcNameSlot) set(name string) {
+	s.name.CompareAndSwap(nil, &name)
+}
+
+func (s *funcNameSlot) get() string {
+	if p := s.name.Load(); p != nil {
+		return *p
+	}
+	return ""
+}
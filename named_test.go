@@ -0,0 +1,37 @@
+package graceful
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestNamed(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	err := Named("cache", func(ctx context.Context) error { return errBoom })(context.Background())
+	if !errors.Is(err, errBoom) || err.Error() != "cache: boom" {
+		t.Fatalf("expected a named error, got %v", err)
+	}
+	if name := FuncName(err); name != "cache" {
+		t.Fatalf("expected the error to be attributed to cache, got %q", name)
+	}
+	if name := FuncName(errBoom); name != "" {
+		t.Fatalf("expected no name for an unnamed error, got %q", name)
+	}
+	if err := Named("cache", func(ctx context.Context) error { return nil })(context.Background()); err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+}
+
+func TestNamedShutdownErrors(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	r := New()
+	r.Shutdown(nil)
+	er := r.Start(nil, []Func{Named("db", func(ctx context.Context) error { return errBoom })})
+
+	if len(er.ErrsShutdown) != 1 || FuncName(er.ErrsShutdown[0]) != "db" {
+		t.Fatalf("expected the shutdown error to be attributed to db, got %v", er.ErrsShutdown)
+	}
+}
@@ -0,0 +1,89 @@
+package graceful
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+)
+
+// Returned by OnShutdown() when ctx was not passed to a startup function, or startup has already ended.
+var ErrNoStartupContext = errors.New("context does not belong to a running startup function")
+
+type hookRegistryKey struct{}
+
+// Shutdown functions registered by startup functions with OnShutdown().
+type hookRegistry struct {
+	mu     sync.Mutex
+	fns    []Func
+	closed bool
+}
+
+// Registers fn to be run during shutdown, given the context passed to a startup function. Lets startup functions
+// register cleanup as they create resources, rather than pre-declaring closures over variables assigned later.
+//
+// Registered functions run after the shutdown functions passed to Start(), in reverse registration order. If startup
+// fails or is aborted, they are run as rollback functions instead.
+func OnShutdown(ctx context.Context, fn Func) error {
+	hr, ok := ctx.Value(hookRegistryKey{}).(*hookRegistry)
+	if !ok {
+		return ErrNoStartupContext
+	}
+
+	hr.mu.Lock()
+	defer hr.mu.Unlock()
+
+	if hr.closed {
+		return ErrNoStartupContext
+	}
+
+	hr.fns = append(hr.fns, fn)
+	return nil
+}
+
+func (hr *hookRegistry) len() int {
+	hr.mu.Lock()
+	defer hr.mu.Unlock()
+
+	return len(hr.fns)
+}
+
+// Stops accepting registrations and returns the registered functions in reverse registration order.
+func (hr *hookRegistry) close() []Func {
+	hr.mu.Lock()
+	defer hr.mu.Unlock()
+
+	hr.closed = true
+	fns := slices.Clone(hr.fns)
+	slices.Reverse(fns)
+	return fns
+}
+
+// Returns a startup function which runs fn and registers the stop function it returns (if non-nil) with OnShutdown(),
+// so acquiring a resource and declaring its teardown happen in one place, like testing.T.Cleanup():
+//
+//	graceful.Start([]graceful.Func{
+//		graceful.StartStop(func(ctx context.Context) (graceful.Func, error) {
+//			db, err := sql.Open("postgres", dsn)
+//			if err != nil {
+//				return nil, err
+//			}
+//			return graceful.Closer(db), nil
+//		}),
+//	}, nil)
+//
+// The stop functions run in reverse order of their startup functions. If the returned function is not run as a
+// startup function, stop is run immediately and ErrNoStartupContext is returned.
+func StartStop(fn func(ctx context.Context) (stop Func, err error)) Func {
+	return func(ctx context.Context) error {
+		stop, err := fn(ctx)
+		if err != nil || stop == nil {
+			return err
+		}
+
+		if err := OnShutdown(ctx, stop); err != nil {
+			return errors.Join(err, stop(context.WithoutCancel(ctx)))
+		}
+		return nil
+	}
+}
@@ -0,0 +1,94 @@
+package graceful
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestOnShutdown(t *testing.T) {
+	var order []string
+	step := func(name string) Func {
+		return func(ctx context.Context) error {
+			order = append(order, name)
+			return nil
+		}
+	}
+
+	var stCtx context.Context
+	open := func(ctx context.Context) error {
+		stCtx = ctx
+		if err := OnShutdown(ctx, step("close a")); err != nil {
+			return err
+		}
+		return OnShutdown(ctx, step("close b"))
+	}
+
+	r := New()
+	r.Shutdown(nil)
+	er := r.Start([]Func{open}, []Func{step("shutdown")})
+
+	if er.ErrStartup != nil || !slices.Equal(order, []string{"shutdown", "close b", "close a"}) {
+		t.Fatalf("unexpected shutdown order: %v (%v)", order, er.ErrStartup)
+	}
+	if err := OnShutdown(stCtx, step("late")); err != ErrNoStartupContext {
+		t.Fatalf("expected ErrNoStartupContext once startup ended, got %v", err)
+	}
+	if err := OnShutdown(context.Background(), step("outside")); err != ErrNoStartupContext {
+		t.Fatalf("expected ErrNoStartupContext outside of startup, got %v", err)
+	}
+}
+
+func TestOnShutdownRunsAsRollback(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	closed := false
+	open := func(ctx context.Context) error {
+		return OnShutdown(ctx, func(ctx context.Context) error {
+			closed = true
+			return nil
+		})
+	}
+	fail := func(ctx context.Context) error { return errBoom }
+
+	er := New().Start([]Func{open, fail}, nil)
+	if !errors.Is(er.ErrStartup, errBoom) || !closed {
+		t.Fatalf("expected the registered function to be rolled back, got closed=%v (%v)", closed, er.ErrStartup)
+	}
+}
+
+func TestStartStop(t *testing.T) {
+	var order []string
+	open := func(name string) Func {
+		return StartStop(func(ctx context.Context) (Func, error) {
+			return func(ctx context.Context) error {
+				order = append(order, "stop "+name)
+				return nil
+			}, nil
+		})
+	}
+	noStop := StartStop(func(ctx context.Context) (Func, error) { return nil, nil })
+
+	r := New()
+	r.Shutdown(nil)
+	er := r.Start([]Func{open("a"), noStop, open("b")}, nil)
+
+	if er.ErrStartup != nil || !slices.Equal(order, []string{"stop b", "stop a"}) {
+		t.Fatalf("expected the stop functions in reverse order, got %v (%v)", order, er.ErrStartup)
+	}
+}
+
+func TestStartStopOutsideStartup(t *testing.T) {
+	stopped := false
+	fn := StartStop(func(ctx context.Context) (Func, error) {
+		return func(ctx context.Context) error {
+			stopped = true
+			return nil
+		}, nil
+	})
+
+	if err := fn(context.Background()); !errors.Is(err, ErrNoStartupContext) || !stopped {
+		t.Fatalf("expected ErrNoStartupContext and an immediate stop, got stopped=%v (%v)", stopped, err)
+	}
+}
@@ -1,66 +1,388 @@
 package graceful
 
 import (
+	"errors"
 	"fmt"
+	"io"
+	"log/slog"
 	"os"
+	"slices"
 	"time"
+
+	"go.opentelemetry.io/otel/trace"
 )
 
-func parseOptions(config *config, opts []*option) error {
-	for _, opt := range opts {
-		switch opt.code {
-		case optionStartupTimeout:
-			if v, ok := opt.value.(time.Duration); ok {
-				if v < 1 {
-					return fmt.Errorf("startup timeout must be positive")
-				}
-				config.startupTimeout = v
-			} else {
-				return fmt.Errorf("failed to cast StartupTimeout to time.Duration")
-			}
+// Configures Start() and its variants. Options are applied in the order provided and return an error (reported in
+// ExitReason.ErrStartup) if their arguments are invalid.
+//
+// A nil Option is ignored, so options can be chosen conditionally. Giving the same timeout or delay option twice with
+// different values is reported as invalid, unless the earlier value came from WithEnvConfig() or
+// WithKubernetesDefaults(), which later options override.
+type Option func(*config) error
 
-		case optionShutdownTimeout:
-			if v, ok := opt.value.(time.Duration); ok {
-				if v < 1 {
-					return fmt.Errorf("shutdown timeout must be positive")
-				}
-				config.shutdownTimeout = v
-			} else {
-				return fmt.Errorf("failed to cast StartupTimeout to time.Duration")
-			}
+// Matched by the errors options return for invalid arguments, ie with errors.Is(er.ErrStartup, ErrInvalidOption).
+var ErrInvalidOption = errors.New("invalid option")
 
-		case optionSignals:
-			if sigs, ok := opt.value.([]os.Signal); ok {
-				config.signals = append(config.signals, sigs...)
-			} else {
-				return fmt.Errorf("failed to cast signals")
-			}
+func invalidOption(format string, args ...any) error {
+	return fmt.Errorf("%w: "+format, append([]any{ErrInvalidOption}, args...)...)
+}
+
+// Sets the duration named setting to d. The options which set durations can only be given once per setting unless the
+// values are equal, since a conflicting repeat is almost always a mistake. Values set by applyDefaults() are not
+// tracked, so they can be overridden.
+func (c *config) setDuration(setting string, dst *time.Duration, d time.Duration) error {
+	if c.defaulting {
+		delete(c.durationsSet, setting)
+	} else {
+		if prev, ok := c.durationsSet[setting]; ok && prev != d {
+			return invalidOption("%s given twice with conflicting values (%s and %s)", setting, prev, d)
+		}
+		if c.durationsSet == nil {
+			c.durationsSet = map[string]time.Duration{}
+		}
+		c.durationsSet[setting] = d
+	}
+
+	*dst = d
+	return nil
+}
+
+// Applies opts on behalf of an option which provides overridable defaults, such as WithKubernetesDefaults().
+func (c *config) applyDefaults(opts ...Option) error {
+	c.defaulting = true
+	defer func() { c.defaulting = false }()
+
+	for _, opt := range opts {
+		if err := opt(c); err != nil {
+			return err
 		}
 	}
+	return nil
+}
+
+func parseOptions(config *config, opts []Option) error {
+	for _, opt := range opts {
+		if opt == nil {
+			continue
+		}
+		if err := opt(config); err != nil {
+			return err
+		}
+	}
+
+	if config.shutdownTimeout > 0 && config.perShutdownTimeout > config.shutdownTimeout {
+		return invalidOption("per shutdown timeout must not exceed the shutdown timeout")
+	}
+	if config.backgroundShutdown > 0 && config.shutdownTimeout <= 0 {
+		return invalidOption("background shutdown requires a shutdown timeout")
+	}
+
+	if config.signalReset && config.forceExit {
+		return invalidOption("signal reset cannot be combined with force exit on second signal")
+	}
+
+	if _, ok := config.reloadFns[config.maintenanceSig]; ok {
+		return invalidOption("maintenance signal must not also be a reload signal")
+	}
+
+	// A reload or maintenance signal must not also trigger shutdown.
+	config.signals = slices.DeleteFunc(dedupSignals(config.signals), func(sig os.Signal) bool {
+		_, ok := config.reloadFns[sig]
+		return ok || sig == config.maintenanceSig
+	})
 
 	return nil
 }
 
+// Removes repeated signals, keeping the first occurrence. os.Interrupt and syscall.SIGINT are the same signal on Unix.
+func dedupSignals(sigs []os.Signal) []os.Signal {
+	var deduped []os.Signal
+	for _, sig := range sigs {
+		if !slices.Contains(deduped, sig) {
+			deduped = append(deduped, sig)
+		}
+	}
+	return deduped
+}
+
 // Maximum amount of time to wait for startup functions to complete before calling a timeout err. Default: unlimited.
-func WithStartupTimeout(d time.Duration) *option {
-	return &option{
-		code:  optionStartupTimeout,
-		value: d,
+func WithStartupTimeout(d time.Duration) Option {
+	return func(config *config) error {
+		if d < 1 {
+			return invalidOption("startup timeout must be positive")
+		}
+		return config.setDuration("startup timeout", &config.startupTimeout, d)
 	}
 }
 
 // Maximum amount of time to wait for shutdown functions to complete before calling a timeout err. Default: unlimited.
-func WithShutdownTimeout(d time.Duration) *option {
-	return &option{
-		code:  optionShutdownTimeout,
-		value: d,
+func WithShutdownTimeout(d time.Duration) Option {
+	return func(config *config) error {
+		if d < 1 {
+			return invalidOption("shutdown timeout must be positive")
+		}
+		return config.setDuration("shutdown timeout", &config.shutdownTimeout, d)
+	}
+}
+
+// Maximum amount of time each shutdown (and rollback) function may take, so a single misbehaving function cannot starve
+// the ones after it. A function still running after d is abandoned and ErrShutdownFuncTimeout is reported. The shutdown
+// timeout still applies to the phase as a whole. Default: unlimited.
+func WithPerShutdownTimeout(d time.Duration) Option {
+	return func(config *config) error {
+		if d < 1 {
+			return invalidOption("per shutdown timeout must be positive")
+		}
+		return config.setDuration("per shutdown timeout", &config.perShutdownTimeout, d)
+	}
+}
+
+// Shutdown functions are run concurrently, at most max at a time, instead of sequentially. Use when they are
+// independent of each other. Rollback functions are always run sequentially. Default: sequential.
+func WithConcurrentShutdown(max int) Option {
+	return func(config *config) error {
+		if max < 1 {
+			return invalidOption("shutdown concurrency must be positive")
+		}
+		config.shutdownConcurrency = max
+		return nil
+	}
+}
+
+// Panics in startup, run, rollback, and shutdown functions are recovered and reported through the normal error fields
+// of ExitReason as a *PanicError, which carries the stack trace. Default: panics crash the process.
+func WithPanicRecovery() Option {
+	return func(config *config) error {
+		config.panicRecovery = true
+		return nil
+	}
+}
+
+// Amount of time to wait after readiness is turned off (see IsReady()) before the shutdown functions are run, so load
+// balancers can stop routing traffic. The shutdown timeout starts after the delay. Default: no delay.
+func WithShutdownDelay(d time.Duration) Option {
+	return func(config *config) error {
+		if d < 0 {
+			return invalidOption("shutdown delay must not be negative")
+		}
+		return config.setDuration("shutdown delay", &config.shutdownDelay, d)
 	}
 }
 
-// These signals will trigger a shutdown. Default: os.Interrupt, syscall.SIGINT, syscall.SIGTERM.
-func WithSignals(sigs []os.Signal) *option {
-	return &option{
-		code:  optionSignals,
-		value: sigs,
+// If another signal is received while shutting down, the process exits immediately with code instead of waiting for
+// the shutdown functions. Default: further signals are ignored.
+func WithForceExitOnSecondSignal(code int) Option {
+	return func(config *config) error {
+		config.forceExit = true
+		config.forceExitCode = code
+		return nil
 	}
 }
+
+// Once shutdown begins, stops trapping the shutdown signals so another one gets Go's default behavior, which usually
+// terminates the process immediately (without running the remaining shutdown functions or returning an ExitReason).
+// Cannot be combined with WithForceExitOnSecondSignal(). See ResetSignals(). Default: further signals are ignored.
+func WithSignalReset() Option {
+	return func(config *config) error {
+		config.signalReset = true
+		return nil
+	}
+}
+
+// If sig is received while shutting down, the remaining shutdown functions are skipped: the partial ExitReason
+// (including a goroutine dump) is written to os.Stderr as indented JSON and the process exits with 128 + the signal
+// number. An escape hatch for when a shutdown function deadlocks and the shutdown timeout is long. sig is only watched
+// once shutdown has begun. Default: none.
+func WithKillSignal(sig os.Signal) Option {
+	return func(config *config) error {
+		if sig == nil {
+			return invalidOption("kill signal must not be nil")
+		}
+		config.killSignal = sig
+		return nil
+	}
+}
+
+// Functions which undo the startup function at the same index. If startup fails or is aborted, the rollback functions
+// of the startup functions which completed are run in reverse order. A nil entry means that step needs no rollback.
+// Default: none.
+func WithStartupRollback(rollbackFns []Func) Option {
+	return func(config *config) error {
+		config.rollbackFns = rollbackFns
+		return nil
+	}
+}
+
+// Functions which run concurrently for the lifetime of the application, such as servers and consumers. They are
+// launched after startup completes and their context is canceled when shutdown begins. If any returns, shutdown is
+// triggered and its error is reported in ExitReason.ErrRuntime. Default: none.
+func WithRun(fns ...Func) Option {
+	return func(config *config) error {
+		config.runFns = append(config.runFns, fns...)
+		return nil
+	}
+}
+
+// Functions which are called once every startup function has completed, before the run functions are launched. Useful
+// for flipping external readiness (service registration, a ready file, sd_notify). They run sequentially on the
+// goroutine which called Start(), so they should return quickly. Default: none.
+func WithOnReady(fns ...func()) Option {
+	return func(config *config) error {
+		config.readyFns = append(config.readyFns, fns...)
+		return nil
+	}
+}
+
+// fn is called as each startup function completes (including best-effort failures, see BestEffort()), with the number
+// completed so far, the total, the function's Named() name (or ""), and how long it took. Useful for showing progress
+// during long cold starts. fn is called on the startup goroutine, so it should return quickly. Default: none.
+func WithProgress(fn func(completed, total int, name string, d time.Duration)) Option {
+	return func(config *config) error {
+		config.progress = fn
+		return nil
+	}
+}
+
+// Run() writes the ExitReason to w as indented JSON before exiting. Ignored by Start(). Default: nothing is written.
+func WithExitWriter(w io.Writer) Option {
+	return func(config *config) error {
+		config.exitWriter = w
+		return nil
+	}
+}
+
+// Lifecycle events (startup/shutdown function timings, signals, timeouts) are logged to l. Default: nothing is logged.
+func WithLogger(l *slog.Logger) Option {
+	return func(config *config) error {
+		config.logger = l
+		return nil
+	}
+}
+
+// Each startup, rollback, and shutdown function is wrapped in a span (named after the function if Named() was used)
+// under a parent "app.startup" or "app.shutdown" span. Default: no spans are created.
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return func(config *config) error {
+		if tp == nil {
+			return invalidOption("tracer provider must not be nil")
+		}
+		config.tracer = tp.Tracer(tracerName)
+		return nil
+	}
+}
+
+// These signals will trigger a shutdown in addition to those already configured. Repeated signals are ignored.
+// Default: os.Interrupt, syscall.SIGINT, syscall.SIGTERM.
+func WithSignals(sigs []os.Signal) Option {
+	return func(config *config) error {
+		if err := validateSignals(sigs); err != nil {
+			return err
+		}
+		config.signals = append(config.signals, sigs...)
+		return nil
+	}
+}
+
+// When sig is received during the run phase, fns are run sequentially while the application keeps running (ie re-read
+// config, reopen log files, reload TLS certificates). Errors are reported to the logger and event subscribers rather
+// than triggering shutdown. sig is removed from the shutdown signals. Default: none.
+func WithReloadSignal(sig os.Signal, fns ...Func) Option {
+	return func(config *config) error {
+		if sig == nil {
+			return invalidOption("reload signal must not be nil")
+		}
+		if config.reloadFns == nil {
+			config.reloadFns = map[os.Signal][]Func{}
+		}
+		config.reloadFns[sig] = append(config.reloadFns[sig], fns...)
+		return nil
+	}
+}
+
+// Selects a shutdown profile per signal, ie a graceful drain for SIGTERM, a fast shutdown with a shorter timeout for
+// SIGINT, and a goroutine dump followed by an immediate exit for SIGQUIT. The signals are added to the shutdown signals.
+// Signals without a policy use the other options. Default: none.
+func WithSignalPolicy(policies map[os.Signal]Policy) Option {
+	return func(config *config) error {
+		if config.policies == nil {
+			config.policies = map[os.Signal]Policy{}
+		}
+		for sig, p := range policies {
+			config.policies[sig] = p
+			config.signals = append(config.signals, sig)
+		}
+		return nil
+	}
+}
+
+// When the shutdown timeout elapses, the stack traces of all goroutines are captured in ExitReason.GoroutineDump and
+// written to w if it is non-nil, to help identify what hung. Default: no dump is captured.
+func WithGoroutineDumpOnTimeout(w io.Writer) Option {
+	return func(config *config) error {
+		config.dumpOnTimeout = true
+		config.dumpWriter = w
+		return nil
+	}
+}
+
+// Every runtime error reported before or during shutdown (via Shutdown(), run functions, and Go()) is collected in
+// ExitReason.ErrsRuntime, rather than only the first in ExitReason.ErrRuntime. Default: only the first is reported.
+func WithAllRuntimeErrors() Option {
+	return func(config *config) error {
+		config.allRuntimeErrs = true
+		return nil
+	}
+}
+
+// Only these signals will trigger a shutdown, replacing the defaults and any provided by earlier options. Repeated
+// signals are ignored.
+func WithSignalsReplace(sigs []os.Signal) Option {
+	return func(config *config) error {
+		if err := validateSignals(sigs); err != nil {
+			return err
+		}
+		config.signals = slices.Clone(sigs)
+		return nil
+	}
+}
+
+// Applies opts in order as a single option, so packages can provide integrations which need several of them (ie
+// WithOnReady() and WithRegistration()). Nil options are skipped.
+func WithOptions(opts ...Option) Option {
+	return func(config *config) error {
+		for _, opt := range opts {
+			if opt == nil {
+				continue
+			}
+			if err := opt(config); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// Builds an option from the Runner it is applied to, for integrations which observe the Runner (ie to publish its
+// Stats()). fn is called each time the option is applied, ie once per lifecycle, and may return nil. It is not called
+// by Validate(), which has no Runner.
+func WithRunner(fn func(r *Runner) Option) Option {
+	return func(config *config) error {
+		if config.runner == nil {
+			return nil
+		}
+		if opt := fn(config.runner); opt != nil {
+			return opt(config)
+		}
+		return nil
+	}
+}
+
+func validateSignals(sigs []os.Signal) error {
+	if len(sigs) == 0 {
+		return invalidOption("signals must not be empty")
+	}
+	if slices.Contains(sigs, nil) {
+		return invalidOption("signals must not contain nil")
+	}
+	return nil
+}
@@ -0,0 +1,157 @@
+package graceful
+
+import (
+	"context"
+	"errors"
+	"os"
+	"slices"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// A signal which exists on every platform, unlike most of those in syscall.
+type testSignal string
+
+func (ts testSignal) String() string { return string(ts) }
+func (ts testSignal) Signal()        {}
+
+func TestSignalOptions(t *testing.T) {
+	hup, usr1 := testSignal("hup"), testSignal("usr1")
+	tests := []struct {
+		name string
+		opts []Option
+		want []os.Signal
+	}{
+		{"appended", []Option{WithSignals([]os.Signal{hup, syscall.SIGTERM})}, []os.Signal{os.Interrupt, syscall.SIGTERM, hup}},
+		{"replaced", []Option{WithSignals([]os.Signal{hup}), WithSignalsReplace([]os.Signal{usr1, usr1})}, []os.Signal{usr1}},
+		{"appended after replace", []Option{WithSignalsReplace([]os.Signal{usr1}), WithSignals([]os.Signal{hup})}, []os.Signal{usr1, hup}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &config{signals: []os.Signal{os.Interrupt, syscall.SIGINT, syscall.SIGTERM}}
+			if err := parseOptions(config, tt.opts); err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(config.signals, tt.want) {
+				t.Fatalf("expected %v, got %v", tt.want, config.signals)
+			}
+		})
+	}
+}
+
+func TestParseOptions(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    []Option
+		wantErr bool
+	}{
+		{"nil options are skipped", []Option{nil, WithStartupTimeout(time.Second), nil}, false},
+		{"custom option", []Option{func(c *config) error { c.forceExit = true; return nil }}, false},
+		{"invalid argument", []Option{WithShutdownTimeout(0)}, true},
+		{"first error wins", []Option{WithStartupTimeout(-1), WithShutdownTimeout(time.Second)}, true},
+		{"per shutdown timeout exceeds shutdown timeout", []Option{WithShutdownTimeout(time.Second), WithPerShutdownTimeout(time.Minute)}, true},
+		{"background shutdown without shutdown timeout", []Option{WithBackgroundShutdown(time.Second)}, true},
+		{"background shutdown with shutdown timeout", []Option{WithShutdownTimeout(time.Second), WithBackgroundShutdown(time.Second)}, false},
+		{"watchdog without timeout", []Option{WithWatchdog(time.Second, 0)}, true},
+		{"conflicting repeat", []Option{WithShutdownTimeout(time.Second), WithShutdownTimeout(time.Minute)}, true},
+		{"equal repeat", []Option{WithShutdownDelay(time.Second), WithShutdownDelay(time.Second)}, false},
+		{"defaults overridden", []Option{WithKubernetesDefaults(30 * time.Second), WithShutdownDelay(time.Second)}, false},
+		{"empty signals", []Option{WithSignalsReplace(nil)}, true},
+		{"nil signal", []Option{WithSignalsReplace([]os.Signal{nil})}, true},
+		{"non-positive budget weight", []Option{WithShutdownBudget(1, 0)}, true},
+		{"signal reset with force exit", []Option{WithSignalReset(), WithForceExitOnSecondSignal(1)}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := parseOptions(&config{}, tt.opts)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected error: %v, got %v", tt.wantErr, err)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidOption) {
+				t.Fatalf("expected ErrInvalidOption, got %v", err)
+			}
+		})
+	}
+}
+
+func TestOnReady(t *testing.T) {
+	var order []string
+	startup := func(ctx context.Context) error {
+		order = append(order, "startup")
+		return nil
+	}
+
+	r := New()
+	r.Shutdown(nil)
+	r.Start([]Func{startup}, nil, WithOnReady(func() { order = append(order, "ready") }))
+	if !slices.Equal(order, []string{"startup", "ready"}) {
+		t.Fatalf("expected the ready function to run after startup, got %v", order)
+	}
+
+	ready := false
+	fail := func(ctx context.Context) error { return errors.New("boom") }
+	New().Start([]Func{fail}, nil, WithOnReady(func() { ready = true }))
+	if ready {
+		t.Fatal("expected the ready function not to run when startup fails")
+	}
+}
+
+func TestWithOptions(t *testing.T) {
+	var config config
+	opt := WithOptions(WithShutdownTimeout(time.Second), nil, WithShutdownDelay(time.Millisecond))
+	if err := opt(&config); err != nil || config.shutdownTimeout != time.Second || config.shutdownDelay != time.Millisecond {
+		t.Fatalf("expected both options applied, got %+v (%v)", config, err)
+	}
+
+	if err := WithOptions(WithShutdownTimeout(0))(&config); err == nil {
+		t.Fatalf("expected the invalid option's error")
+	}
+}
+
+func TestProgress(t *testing.T) {
+	errBoom := errors.New("boom")
+	ok := func(ctx context.Context) error { return nil }
+	optional := BestEffort(func(ctx context.Context) error { return errBoom })
+
+	type report struct {
+		completed, total int
+		name             string
+	}
+	var reports []report
+	progress := func(completed, total int, name string, d time.Duration) {
+		reports = append(reports, report{completed, total, name})
+	}
+
+	r := New()
+	r.Shutdown(nil)
+	r.Start([]Func{Named("db", ok), optional}, nil, WithProgress(progress))
+
+	expected := []report{{1, 2, "db"}, {2, 2, ""}}
+	if !slices.Equal(reports, expected) {
+		t.Fatalf("expected %v, got %v", expected, reports)
+	}
+}
+
+func TestWithRunner(t *testing.T) {
+	r := New()
+
+	var got *Runner
+	opt := WithRunner(func(r *Runner) Option {
+		got = r
+		return WithShutdownTimeout(time.Second)
+	})
+
+	r.Shutdown(nil)
+	r.Start(nil, nil, opt)
+	if got != r {
+		t.Fatalf("expected the Runner the option was given to, got %p", got)
+	}
+
+	got = nil
+	if err := Validate(nil, nil, opt); err != nil || got != nil {
+		t.Fatalf("expected Validate() not to call fn, got %p (%v)", got, err)
+	}
+}
@@ -0,0 +1,129 @@
+package graceful
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+)
+
+// When the shutdown timeout elapses, Start() returns as usual, but the shutdown functions which have not returned keep
+// running in the background (and those not yet started are still run) for up to d longer. Their context is only
+// canceled once that hard deadline elapses. Which functions completed late and which were abandoned is reported by
+// ExitReason.Overrun. Requires WithShutdownTimeout(), otherwise startup fails. Default: shutdown functions are
+// abandoned at the shutdown timeout.
+func WithBackgroundShutdown(d time.Duration) Option {
+	return func(config *config) error {
+		if d < 1 {
+			return invalidOption("background shutdown must be positive")
+		}
+		config.backgroundShutdown = d
+		return nil
+	}
+}
+
+// Tracks the shutdown functions which were still running when the shutdown timeout elapsed. See
+// WithBackgroundShutdown().
+type Overrun struct {
+	done chan struct{}
+
+	mu        sync.Mutex
+	late      []StepTiming
+	abandoned []string
+	closed    bool
+}
+
+// Returns a channel which is closed once every background shutdown function has returned or the hard deadline elapsed.
+func (o *Overrun) Done() <-chan struct{} {
+	return o.done
+}
+
+// Blocks until Done() is closed. Returns the timings of the shutdown functions which completed after the shutdown
+// timeout, and the names (as given by Named() or "shutdown[i]" otherwise) of those which had not completed by the
+// hard deadline.
+func (o *Overrun) Wait() (late []StepTiming, abandoned []string) {
+	<-o.done
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	return slices.Clone(o.late), slices.Clone(o.abandoned)
+}
+
+func (o *Overrun) addLate(st StepTiming) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if !o.closed {
+		o.late = append(o.late, st)
+	}
+}
+
+// Runs the shutdown functions sequentially, or concurrently with WithConcurrentShutdown(). If WithBackgroundShutdown()
+// was provided and ctx is done before they all return, the rest are left running and tracked by the returned Overrun.
+func (c *config) runShutdown(ctx context.Context, fns []Func, slots []*funcNameSlot) ([]error, *Overrun) {
+	fnCtx, fnCancel := ctx, context.CancelFunc(nop)
+	if c.backgroundShutdown > 0 {
+		fnCtx, fnCancel = c.withTimeoutCause(context.WithoutCancel(ctx), c.shutdownTimeout+c.backgroundShutdown, ErrShutdownTimeout)
+	}
+
+	var results <-chan fnResult
+	if c.shutdownConcurrency > 0 {
+		results = launchConcurrently(fnCtx, fns, c.shutdownConcurrency)
+	} else {
+		results = launchSequentially(fnCtx, fns)
+	}
+
+	errs, returned := collect(ctx, results, len(fns))
+	errs = attributeTimeout("shutdown", errs, returned, slots)
+	if fnCtx == ctx || !slices.Contains(returned, false) {
+		fnCancel()
+		return errs, nil
+	}
+
+	o := &Overrun{done: make(chan struct{})}
+	c.steps.redirect(o.addLate)
+	c.log(slog.LevelWarn, "shutdown continuing in background", slog.Duration("deadline", c.backgroundShutdown))
+
+	go func() {
+		defer close(o.done)
+		defer fnCancel()
+
+		pending := 0
+		for _, ok := range returned {
+			if !ok {
+				pending++
+			}
+		}
+
+	wait:
+		for pending > 0 {
+			select {
+			case res := <-results:
+				returned[res.index] = true
+				pending--
+			case <-fnCtx.Done():
+				break wait
+			}
+		}
+
+		o.mu.Lock()
+		defer o.mu.Unlock()
+
+		o.closed = true
+		for i, ok := range returned {
+			if !ok {
+				o.abandoned = append(o.abandoned, slotName("shutdown", i, slots[i]))
+			}
+		}
+		// A function may have returned in reaction to the hard deadline while it was being abandoned.
+		o.late = slices.DeleteFunc(o.late, func(st StepTiming) bool {
+			return !returned[st.Index]
+		})
+		c.log(slog.LevelInfo, "background shutdown completed", slog.Int("late", len(o.late)),
+			slog.Any("abandoned", o.abandoned))
+	}()
+
+	return errs, o
+}
@@ -0,0 +1,49 @@
+package graceful
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestBackgroundShutdown(t *testing.T) {
+	release := make(chan struct{})
+	slow := Named("slow", func(ctx context.Context) error {
+		<-release
+		return nil
+	})
+	stuck := Named("stuck", hang)
+
+	r := New()
+	r.Shutdown(nil)
+	er := r.Start(nil, []Func{slow, stuck}, WithShutdownTimeout(20*time.Millisecond), WithBackgroundShutdown(100*time.Millisecond))
+
+	if !errors.Is(errors.Join(er.ErrsShutdown...), ErrShutdownTimeout) {
+		t.Fatalf("expected the shutdown timeout to elapse, got %v", er.ErrsShutdown)
+	}
+	if er.Overrun == nil {
+		t.Fatal("expected the remaining shutdown functions to continue in the background")
+	}
+
+	close(release)
+	late, abandoned := er.Overrun.Wait()
+	if len(late) != 1 || late[0].Name != "slow" {
+		t.Fatalf("expected slow to complete late, got %+v", late)
+	}
+	if !slices.Equal(abandoned, []string{"stuck"}) {
+		t.Fatalf("expected stuck to be abandoned, got %v", abandoned)
+	}
+}
+
+func TestBackgroundShutdownNotNeeded(t *testing.T) {
+	r := New()
+	r.Shutdown(nil)
+	er := r.Start(nil, []Func{func(ctx context.Context) error { return nil }},
+		WithShutdownTimeout(time.Second), WithBackgroundShutdown(time.Second))
+
+	if len(er.ErrsShutdown) > 0 || er.Overrun != nil {
+		t.Fatalf("expected shutdown to complete in time, got %v, %v", er.ErrsShutdown, er.Overrun)
+	}
+}
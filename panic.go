@@ -0,0 +1,47 @@
+package graceful
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+)
+
+// Reported in place of an error when a lifecycle function panics and WithPanicRecovery() was provided.
+type PanicError struct {
+	Value any
+	Stack []byte
+}
+
+func (pe *PanicError) Error() string {
+	return fmt.Sprintf("panic: %v\n\n%s", pe.Value, pe.Stack)
+}
+
+// Unwraps the panic value if it is an error.
+func (pe *PanicError) Unwrap() error {
+	if err, ok := pe.Value.(error); ok {
+		return err
+	}
+	return nil
+}
+
+// Wraps fns so panics are returned as a *PanicError. Returns fns unchanged unless WithPanicRecovery() was provided.
+func (c *config) recoverPanics(fns []Func) []Func {
+	if !c.panicRecovery {
+		return fns
+	}
+
+	wrapped := make([]Func, len(fns))
+	for i, fn := range fns {
+		wrapped[i] = func(ctx context.Context) (err error) {
+			defer func() {
+				if v := recover(); v != nil {
+					err = &PanicError{Value: v, Stack: debug.Stack()}
+				}
+			}()
+
+			return fn(ctx)
+		}
+	}
+
+	return wrapped
+}
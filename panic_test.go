@@ -0,0 +1,36 @@
+package graceful
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestPanicRecovery(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	r := New()
+	r.Shutdown(nil)
+
+	er := r.Start(nil, []Func{func(ctx context.Context) error { panic(errBoom) }}, WithPanicRecovery())
+
+	var pe *PanicError
+	if len(er.ErrsShutdown) != 1 || !errors.As(er.ErrsShutdown[0], &pe) || !errors.Is(pe, errBoom) {
+		t.Fatalf("expected a PanicError wrapping errBoom, got %v", er.ErrsShutdown)
+	}
+	if !strings.Contains(string(pe.Stack), "panic_test.go") {
+		t.Fatalf("expected the stack to include the panicking function, got %s", pe.Stack)
+	}
+}
+
+func TestPanicRecoveryDuringStartup(t *testing.T) {
+	r := New()
+
+	er := r.Start([]Func{func(ctx context.Context) error { panic("oops") }}, nil, WithPanicRecovery())
+
+	var pe *PanicError
+	if !errors.As(er.ErrStartup, &pe) || pe.Value != "oops" || pe.Unwrap() != nil {
+		t.Fatalf("expected a PanicError, got %v", er.ErrStartup)
+	}
+}
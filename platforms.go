@@ -0,0 +1,78 @@
+package graceful
+
+import (
+	"os"
+	"syscall"
+	"time"
+)
+
+// Reports whether the process runs on Google Cloud Run, as a service or a job.
+func OnCloudRun() bool {
+	return os.Getenv("K_SERVICE") != "" || os.Getenv("CLOUD_RUN_JOB") != ""
+}
+
+// Reports whether the process runs in an Amazon ECS task.
+func OnECS() bool {
+	return os.Getenv("ECS_CONTAINER_METADATA_URI_V4") != "" || os.Getenv("ECS_CONTAINER_METADATA_URI") != ""
+}
+
+// Reports whether the process runs in a Heroku dyno.
+func OnHeroku() bool {
+	return os.Getenv("DYNO") != ""
+}
+
+// Reports whether the process runs in a Kubernetes pod.
+func OnKubernetes() bool {
+	return os.Getenv("KUBERNETES_SERVICE_HOST") != ""
+}
+
+// Applies best practices for Google Cloud Run, which sends SIGTERM and then SIGKILL 10s later. Only SIGTERM triggers
+// shutdown and the shutdown timeout is 9s. No shutdown delay is needed, since Cloud Run stops routing requests to an
+// instance before sending SIGTERM. Options after this one override it.
+func WithCloudRunDefaults() Option {
+	return withGracePeriod(10 * time.Second)
+}
+
+// Applies best practices for Amazon ECS, which sends SIGTERM and then SIGKILL after the container's stopTimeout (30s
+// unless configured otherwise). Only SIGTERM triggers shutdown and the shutdown timeout is 27s. No shutdown delay is
+// needed, since tasks are deregistered from their load balancer before SIGTERM is sent. Options after this one
+// override it; use WithShutdownTimeout() for a custom stopTimeout.
+func WithECSDefaults() Option {
+	return withGracePeriod(30 * time.Second)
+}
+
+// Applies best practices for Heroku, which sends SIGTERM and then SIGKILL 30s later. Only SIGTERM triggers shutdown
+// and the shutdown timeout is 27s. Options after this one override it.
+func WithHerokuDefaults() Option {
+	return withGracePeriod(30 * time.Second)
+}
+
+// Applies the defaults of the platform the process runs on, as detected by OnCloudRun(), OnECS(), OnHeroku(), and
+// OnKubernetes() (with the default terminationGracePeriodSeconds of 30s, see WithKubernetesDefaults()). Does nothing
+// if no platform is detected. Options after this one override it.
+func WithPlatformDefaults() Option {
+	return func(config *config) error {
+		switch {
+		case OnCloudRun():
+			return WithCloudRunDefaults()(config)
+		case OnECS():
+			return WithECSDefaults()(config)
+		case OnHeroku():
+			return WithHerokuDefaults()(config)
+		case OnKubernetes():
+			return WithKubernetesDefaults(30 * time.Second)(config)
+		default:
+			return nil
+		}
+	}
+}
+
+// Only SIGTERM triggers shutdown, which must complete within gracePeriod less a tenth as a safety buffer.
+func withGracePeriod(gracePeriod time.Duration) Option {
+	return func(config *config) error {
+		return config.applyDefaults(
+			WithSignalsReplace([]os.Signal{syscall.SIGTERM}),
+			WithShutdownTimeout(gracePeriod-gracePeriod/10),
+		)
+	}
+}
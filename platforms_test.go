@@ -0,0 +1,66 @@
+package graceful
+
+import (
+	"os"
+	"slices"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// Clears the platform detection variables for the duration of the test.
+func clearPlatformEnv(t *testing.T) {
+	t.Helper()
+
+	for _, key := range []string{"K_SERVICE", "CLOUD_RUN_JOB", "ECS_CONTAINER_METADATA_URI_V4", "ECS_CONTAINER_METADATA_URI", "DYNO", "KUBERNETES_SERVICE_HOST"} {
+		t.Setenv(key, "")
+	}
+}
+
+func TestPlatformDefaults(t *testing.T) {
+	tests := []struct {
+		name    string
+		env     string
+		timeout time.Duration
+		delay   time.Duration
+	}{
+		{"cloud run", "K_SERVICE", 9 * time.Second, 0},
+		{"ecs", "ECS_CONTAINER_METADATA_URI_V4", 27 * time.Second, 0},
+		{"heroku", "DYNO", 27 * time.Second, 0},
+		{"kubernetes", "KUBERNETES_SERVICE_HOST", 20 * time.Second, 5 * time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearPlatformEnv(t)
+			t.Setenv(tt.env, "1")
+
+			c := &config{}
+			if err := parseOptions(c, []Option{WithPlatformDefaults()}); err != nil {
+				t.Fatal(err)
+			}
+			if c.shutdownTimeout != tt.timeout || c.shutdownDelay != tt.delay {
+				t.Fatalf("expected a %v timeout and %v delay, got %v and %v", tt.timeout, tt.delay, c.shutdownTimeout,
+					c.shutdownDelay)
+			}
+			if !slices.Equal(c.signals, []os.Signal{syscall.SIGTERM}) {
+				t.Fatalf("expected only SIGTERM, got %v", c.signals)
+			}
+		})
+	}
+}
+
+func TestPlatformDefaultsUndetected(t *testing.T) {
+	clearPlatformEnv(t)
+
+	c := &config{}
+	if err := parseOptions(c, []Option{WithPlatformDefaults()}); err != nil || c.shutdownTimeout != 0 {
+		t.Fatalf("expected no defaults without a platform, got %v (%v)", c.shutdownTimeout, err)
+	}
+
+	// Later options override the preset.
+	c = &config{}
+	if err := parseOptions(c, []Option{WithHerokuDefaults(), WithShutdownTimeout(time.Second)}); err != nil || c.shutdownTimeout != time.Second {
+		t.Fatalf("expected the later timeout to win, got %v (%v)", c.shutdownTimeout, err)
+	}
+}
@@ -0,0 +1,82 @@
+package graceful
+
+import (
+	"io"
+	"log/slog"
+	"os"
+	"runtime"
+	"time"
+)
+
+// Selects how the application shuts down in response to a particular signal. See WithSignalPolicy().
+type Policy struct {
+	// Overrides WithShutdownTimeout() if positive.
+	ShutdownTimeout time.Duration
+
+	// Overrides WithShutdownDelay() if positive. SkipShutdownDelay disables the delay instead.
+	ShutdownDelay     time.Duration
+	SkipShutdownDelay bool
+
+	// Writes the stack traces of all goroutines to DumpWriter (os.Stderr if nil) before shutting down.
+	DumpGoroutines bool
+	DumpWriter     io.Writer
+
+	// Exits the process immediately with ExitCode instead of running the shutdown functions.
+	Exit     bool
+	ExitCode int
+}
+
+// Returns a copy of config with the policy for er's signal, if any, applied. config itself is left unchanged since
+// goroutines started earlier in the lifecycle may still be reading it. May exit the process, in which case er is written
+// to the file given to WithExitReasonFile().
+func applyPolicy(config *config, er *ExitReason) *config {
+	sig := er.OsSignal
+	p, ok := config.policies[sig]
+	if !ok {
+		return config
+	}
+
+	c := *config
+	config = &c
+
+	if p.ShutdownTimeout > 0 {
+		config.shutdownTimeout = p.ShutdownTimeout
+	}
+
+	if p.SkipShutdownDelay {
+		config.shutdownDelay = 0
+	} else if p.ShutdownDelay > 0 {
+		config.shutdownDelay = p.ShutdownDelay
+	}
+
+	if p.DumpGoroutines {
+		w := p.DumpWriter
+		if w == nil {
+			w = os.Stderr
+		}
+		w.Write(goroutineDump())
+	}
+
+	if p.Exit {
+		config.log(slog.LevelWarn, "exiting immediately due to signal policy",
+			slog.String("signal", sig.String()), slog.Int("code", p.ExitCode))
+		er.Timing.Steps = config.steps.snapshot()
+		writeExitReasonFile(config, er)
+		config.flushErrsBeforeExit()
+		osExit(p.ExitCode)
+	}
+
+	return config
+}
+
+// Returns the stack traces of all goroutines.
+func goroutineDump() []byte {
+	buf := make([]byte, 1<<16)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) {
+			return buf[:n]
+		}
+		buf = make([]byte, 2*len(buf))
+	}
+}
@@ -0,0 +1,95 @@
+package graceful
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// Signals the process once running and waits for shutdown.
+func runUntilSignaled(ctx context.Context) error {
+	if err := signalSelf(syscall.SIGTERM); err != nil {
+		return err
+	}
+	<-ctx.Done()
+	return nil
+}
+
+func TestSignalPolicy(t *testing.T) {
+	var dump bytes.Buffer
+	var remaining time.Duration
+	shutdown := func(ctx context.Context) error {
+		deadline, _ := ctx.Deadline()
+		remaining = time.Until(deadline)
+		return nil
+	}
+
+	r := New()
+	er := r.Start(nil, []Func{shutdown},
+		WithRun(runUntilSignaled),
+		WithShutdownTimeout(time.Hour),
+		WithShutdownDelay(time.Hour),
+		WithSignalPolicy(map[os.Signal]Policy{
+			syscall.SIGTERM: {ShutdownTimeout: time.Minute, SkipShutdownDelay: true, DumpGoroutines: true, DumpWriter: &dump},
+		}),
+	)
+
+	if er.OsSignal != syscall.SIGTERM || er.Timing.ShutdownDelay != 0 {
+		t.Fatalf("expected SIGTERM without a shutdown delay, got %v", er)
+	}
+	if remaining <= 0 || remaining > time.Minute {
+		t.Fatalf("expected the policy's shutdown timeout, %v remained", remaining)
+	}
+	if !strings.Contains(dump.String(), "goroutine") {
+		t.Fatalf("expected a goroutine dump, got %q", dump.String())
+	}
+}
+
+func TestSignalPolicyExit(t *testing.T) {
+	codes := stubExit(t)
+	path := filepath.Join(t.TempDir(), "exit.json")
+
+	// Runs after the stubbed exit, before the lifecycle replaces the file.
+	var written *ExitReason
+	load := func(ctx context.Context) error {
+		var err error
+		written, err = LoadLastExitReason(path)
+		return err
+	}
+
+	r := New()
+	er := r.Start(nil, []Func{load},
+		WithRun(runUntilSignaled),
+		WithSignalPolicy(map[os.Signal]Policy{syscall.SIGTERM: {Exit: true, ExitCode: 3}}),
+		WithExitReasonFile(path),
+	)
+
+	if code := <-codes; code != 3 {
+		t.Fatalf("expected exit code 3, got %d", code)
+	}
+	if len(er.ErrsShutdown) != 0 || written == nil || written.OsSignal == nil {
+		t.Fatalf("expected the exit reason to be written before exiting, got %v (%v)", written, er.ErrsShutdown)
+	}
+}
+
+func TestSignalPolicyLeavesConfigUnchanged(t *testing.T) {
+	config := &config{
+		shutdownTimeout: time.Hour,
+		policies:        map[os.Signal]Policy{syscall.SIGTERM: {ShutdownTimeout: time.Minute}},
+	}
+
+	if applied := applyPolicy(config, &ExitReason{OsSignal: syscall.SIGTERM}); applied == config || applied.shutdownTimeout != time.Minute {
+		t.Fatalf("expected a copy with the policy applied, got %+v", applied)
+	}
+	if config.shutdownTimeout != time.Hour {
+		t.Fatalf("expected the original config to be unchanged, got %v", config.shutdownTimeout)
+	}
+	if applyPolicy(config, &ExitReason{OsSignal: os.Interrupt}) != config {
+		t.Fatal("expected config to be returned as is without a policy")
+	}
+}
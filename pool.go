@@ -0,0 +1,199 @@
+package graceful
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+)
+
+// Returned by Pool.Submit() once the pool has begun shutting down.
+var ErrPoolClosed = errors.New("pool is closed")
+
+// A fixed number of workers which run jobs submitted during the run phase, and drain them during shutdown:
+//
+//	pool := graceful.NewPool(8, 100, nil)
+//	graceful.Start(nil, []graceful.Func{pool.Shutdown}, graceful.WithRun(pool.Run))
+//
+// Jobs receive a context which is not canceled when shutdown begins, only once the shutdown context of
+// Pool.Shutdown() is done, so in-flight jobs can complete.
+type Pool struct {
+	workers int
+	jobs    chan Func
+	onErr   func(error)
+
+	mu         sync.RWMutex
+	closed     bool
+	closing    chan struct{}
+	submitting sync.WaitGroup
+
+	jobCtx    context.Context
+	jobCancel context.CancelFunc
+	inFlight  atomic.Int64
+	done      chan struct{}
+	startOnce sync.Once
+}
+
+// Creates a Pool of workers which runs jobs from a queue of queueSize. Errors returned by jobs are passed to onErr,
+// or discarded if it is nil.
+func NewPool(workers int, queueSize int, onErr func(error)) *Pool {
+	jobCtx, jobCancel := context.WithCancel(context.Background())
+	return &Pool{
+		workers:   max(workers, 1),
+		jobs:      make(chan Func, max(queueSize, 0)),
+		onErr:     onErr,
+		closing:   make(chan struct{}),
+		jobCtx:    jobCtx,
+		jobCancel: jobCancel,
+		done:      make(chan struct{}),
+	}
+}
+
+// Queues job, blocking while the queue is full. Returns ErrPoolClosed once shutdown has begun (even while blocked), or
+// ctx's error if it is done first. Jobs may be submitted before Run() is called, but they only start once it is.
+func (p *Pool) Submit(ctx context.Context, job Func) error {
+	// The workers wait for the submissions in progress once the pool closes, so a queued job is never missed by them.
+	p.mu.RLock()
+	if p.closed {
+		p.mu.RUnlock()
+		return ErrPoolClosed
+	}
+	p.submitting.Add(1)
+	p.mu.RUnlock()
+	defer p.submitting.Done()
+
+	select {
+	case p.jobs <- job:
+		return nil
+	case <-p.closing:
+		return ErrPoolClosed
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// A run function which starts the workers. Once ctx is done, the pool stops accepting jobs and Run() returns after the
+// queued and in-flight jobs are drained (or abandoned by Shutdown()).
+//
+// A Pool only runs once: Run() returns ErrPoolClosed once the pool has been shut down, so a restarted lifecycle (see
+// SuperviseFactory()) must create a new one.
+func (p *Pool) Run(ctx context.Context) error {
+	p.mu.RLock()
+	closed := p.closed
+	p.mu.RUnlock()
+	if closed {
+		return ErrPoolClosed
+	}
+
+	p.startOnce.Do(func() {
+		var wg sync.WaitGroup
+		for range p.workers {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				p.work()
+			}()
+		}
+
+		go func() {
+			wg.Wait()
+			close(p.done)
+		}()
+	})
+
+	select {
+	case <-ctx.Done():
+		p.close()
+	case <-p.closing:
+	}
+
+	<-p.done
+	return nil
+}
+
+// A shutdown function which stops accepting jobs and waits for the queued and in-flight jobs to complete. If ctx is
+// done first, the context passed to the jobs is canceled, the queued jobs are dropped, and an error reporting how
+// many jobs were abandoned is returned. The same error is returned if Run() was never called, since nothing can run the
+// queued jobs.
+func (p *Pool) Shutdown(ctx context.Context) error {
+	p.close()
+
+	// A pool which never ran has no workers to drain the queue, and must not start them once closed.
+	neverRan := false
+	p.startOnce.Do(func() {
+		neverRan = true
+		close(p.done)
+	})
+	if neverRan {
+		p.submitting.Wait()
+	}
+	if neverRan && len(p.jobs) > 0 {
+		return fmt.Errorf("%d job(s) abandoned: %w", len(p.jobs), ErrPoolClosed)
+	}
+
+	select {
+	case <-p.done:
+		return nil
+	case <-ctx.Done():
+	}
+
+	// Count before canceling, since the in-flight jobs may return as soon as they observe the cancellation.
+	abandoned := int(p.inFlight.Load()) + len(p.jobs)
+	p.jobCancel()
+	return fmt.Errorf("%d job(s) abandoned: %w", abandoned, ctx.Err())
+}
+
+// Returns the number of jobs which are running.
+func (p *Pool) InFlight() int {
+	return int(p.inFlight.Load())
+}
+
+// Returns the number of jobs which are queued but have not started.
+func (p *Pool) Queued() int {
+	return len(p.jobs)
+}
+
+func (p *Pool) close() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if !p.closed {
+		p.closed = true
+		close(p.closing)
+	}
+}
+
+func (p *Pool) work() {
+	for {
+		select {
+		case job := <-p.jobs:
+			p.run(job)
+		case <-p.closing:
+			// Drain the queue. Nothing can be queued once closing is closed and the submissions in progress return.
+			p.submitting.Wait()
+			for {
+				select {
+				case job := <-p.jobs:
+					p.run(job)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+func (p *Pool) run(job Func) {
+	// Jobs still queued once Shutdown() gives up are dropped.
+	if p.jobCtx.Err() != nil {
+		return
+	}
+
+	p.inFlight.Add(1)
+	defer p.inFlight.Add(-1)
+
+	if err := job(p.jobCtx); err != nil && p.onErr != nil {
+		p.onErr(err)
+	}
+}
@@ -0,0 +1,104 @@
+package graceful
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestPoolDrainsQueuedJobs(t *testing.T) {
+	pool := NewPool(2, 10, nil)
+
+	ran := make(chan struct{}, 10)
+	for range 10 {
+		err := pool.Submit(context.Background(), func(ctx context.Context) error {
+			ran <- struct{}{}
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	runErr := make(chan error, 1)
+	go func() { runErr <- pool.Run(ctx) }()
+
+	cancel()
+	if err := <-runErr; err != nil {
+		t.Fatal(err)
+	}
+	if err := pool.Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if len(ran) != 10 {
+		t.Fatalf("expected every queued job to run, got %d", len(ran))
+	}
+	if err := pool.Submit(context.Background(), nil); !errors.Is(err, ErrPoolClosed) {
+		t.Fatalf("expected ErrPoolClosed, got %v", err)
+	}
+}
+
+func TestPoolShutdownWithFullQueue(t *testing.T) {
+	pool := NewPool(1, 1, nil)
+	go pool.Run(context.Background())
+
+	block := func(ctx context.Context) error {
+		<-ctx.Done()
+		return nil
+	}
+
+	// One job runs and one fills the queue, so the next submission blocks.
+	for range 2 {
+		if err := pool.Submit(context.Background(), block); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for pool.InFlight() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	submitErr := make(chan error, 1)
+	go func() { submitErr <- pool.Submit(context.Background(), block) }()
+	time.Sleep(20 * time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	shutdownErr := make(chan error, 1)
+	go func() { shutdownErr <- pool.Shutdown(ctx) }()
+
+	select {
+	case err := <-shutdownErr:
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("expected the jobs to be abandoned, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected Shutdown() to respect its context while a submission is blocked")
+	}
+
+	if err := <-submitErr; err != nil && !errors.Is(err, ErrPoolClosed) {
+		t.Fatalf("expected the blocked submission to be queued or rejected, got %v", err)
+	}
+}
+
+func TestPoolRunAfterShutdown(t *testing.T) {
+	pool := NewPool(1, 1, nil)
+	if err := pool.Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if err := pool.Run(context.Background()); !errors.Is(err, ErrPoolClosed) {
+		t.Fatalf("expected ErrPoolClosed, got %v", err)
+	}
+}
+
+func TestPoolShutdownWithoutRun(t *testing.T) {
+	pool := NewPool(1, 1, nil)
+	if err := pool.Submit(context.Background(), func(ctx context.Context) error { return nil }); err != nil {
+		t.Fatal(err)
+	}
+	if err := pool.Shutdown(context.Background()); !errors.Is(err, ErrPoolClosed) {
+		t.Fatalf("expected the queued job to be abandoned, got %v", err)
+	}
+}
@@ -0,0 +1,66 @@
+// Package pprof runs net/http/pprof on a private port for the lifetime of a graceful application, and writes profiles
+// to disk on demand.
+//
+// It is separate from package graceful because importing net/http/pprof registers its handlers on
+// http.DefaultServeMux, which must not happen to applications that do not ask for it.
+package pprof
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	httppprof "net/http/pprof"
+	"os"
+	"path/filepath"
+	rtpprof "runtime/pprof"
+	"time"
+
+	"github.com/bryhen/graceful"
+)
+
+// Serves the pprof endpoints under /debug/pprof/ on addr (ie "localhost:6060"). Pass run to graceful.WithRun() and
+// stop to the shutdown functions. The server uses its own mux, so the endpoints are only exposed on addr unless the
+// application also serves http.DefaultServeMux.
+func Server(addr string) (run graceful.Func, stop graceful.Func) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", httppprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", httppprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", httppprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", httppprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", httppprof.Trace)
+
+	return graceful.HTTPServer(&http.Server{Addr: addr, Handler: mux}, nil)
+}
+
+// Returns a function which writes the heap and goroutine profiles to dir, named with the time they were taken (ie
+// "heap-20240102T150405.pprof"). Register it as a reload function to take profiles without terminating the app:
+//
+//	graceful.WithReloadSignal(syscall.SIGUSR1, pprof.WriteProfiles("/tmp/profiles"))
+func WriteProfiles(dir string) graceful.Func {
+	return func(ctx context.Context) error {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return err
+		}
+
+		stamp := time.Now().Format("20060102T150405")
+		for _, name := range []string{"heap", "goroutine"} {
+			if err := writeProfile(name, filepath.Join(dir, name+"-"+stamp+".pprof")); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+func writeProfile(name string, path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if err := rtpprof.Lookup(name).WriteTo(f, 0); err != nil {
+		return fmt.Errorf("failed to write %s profile: %w", name, err)
+	}
+	return f.Close()
+}
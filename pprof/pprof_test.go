@@ -0,0 +1,64 @@
+package pprof
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestServer(t *testing.T) {
+	// Reserve a free port, since Server() listens on an address rather than a listener.
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("cannot listen: %v", err)
+	}
+	addr := ln.Addr().String()
+	ln.Close()
+
+	run, stop := Server(addr)
+	done := make(chan error, 1)
+	go func() { done <- run(context.Background()) }()
+
+	var res *http.Response
+	for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+		if res, err = http.Get("http://" + addr + "/debug/pprof/"); err == nil {
+			break
+		}
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		t.Fatalf("expected the pprof index, got %d", res.StatusCode)
+	}
+
+	if err := stop(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if err := <-done; err != nil {
+		t.Fatalf("expected a clean exit, got %v", err)
+	}
+}
+
+func TestWriteProfiles(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "profiles")
+
+	if err := WriteProfiles(dir)(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{"heap", "goroutine"} {
+		matches, err := filepath.Glob(filepath.Join(dir, name+"-*.pprof"))
+		if err != nil || len(matches) != 1 {
+			t.Fatalf("expected one %s profile, got %v (%v)", name, matches, err)
+		}
+		if fi, err := os.Stat(matches[0]); err != nil || fi.Size() == 0 {
+			t.Fatalf("expected a non-empty %s profile, got %v", name, err)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package graceful
+
+import (
+	"context"
+	"fmt"
+	"os/user"
+	"strconv"
+)
+
+// Returns a startup function which switches the process to the given user and group (names or numeric IDs), ie after
+// earlier startup functions have bound ports below 1024 as root. If group is empty, the user's primary group is used.
+// The supplementary groups are replaced with the group.
+//
+// Place it after the startup functions which need the privileges. Only supported on Linux and other unix systems;
+// elsewhere it fails with an error matching errors.ErrUnsupported.
+func DropPrivileges(username, group string) Func {
+	return func(ctx context.Context) error {
+		if !dropPrivilegesSupported {
+			return errDropPrivilegesUnsupported
+		}
+
+		u, err := user.Lookup(username)
+		if err != nil {
+			if u, err = user.LookupId(username); err != nil {
+				return fmt.Errorf("drop privileges: unknown user %q", username)
+			}
+		}
+
+		gidStr := u.Gid
+		if group != "" {
+			g, err := user.LookupGroup(group)
+			if err != nil {
+				if g, err = user.LookupGroupId(group); err != nil {
+					return fmt.Errorf("drop privileges: unknown group %q", group)
+				}
+			}
+			gidStr = g.Gid
+		}
+
+		uid, err := strconv.Atoi(u.Uid)
+		if err != nil {
+			return fmt.Errorf("drop privileges: user %q has non-numeric id %q", username, u.Uid)
+		}
+		gid, err := strconv.Atoi(gidStr)
+		if err != nil {
+			return fmt.Errorf("drop privileges: group has non-numeric id %q", gidStr)
+		}
+
+		if err := setIDs(uid, gid); err != nil {
+			return fmt.Errorf("drop privileges to %s:%d: %w", username, gid, err)
+		}
+		return nil
+	}
+}
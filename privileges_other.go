@@ -0,0 +1,16 @@
+//go:build !unix
+
+package graceful
+
+import (
+	"errors"
+	"fmt"
+)
+
+const dropPrivilegesSupported = false
+
+var errDropPrivilegesUnsupported = fmt.Errorf("drop privileges: only supported on unix: %w", errors.ErrUnsupported)
+
+func setIDs(uid, gid int) error {
+	return errDropPrivilegesUnsupported
+}
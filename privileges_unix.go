@@ -0,0 +1,31 @@
+//go:build unix
+
+package graceful
+
+import (
+	"fmt"
+	"syscall"
+)
+
+const dropPrivilegesSupported = true
+
+var errDropPrivilegesUnsupported error
+
+// The group must change first, since the process may no longer change it once it is not root.
+func setIDs(uid, gid int) error {
+	if err := syscall.Setgroups([]int{gid}); err != nil {
+		return fmt.Errorf("setgroups: %w", err)
+	}
+	if err := syscall.Setgid(gid); err != nil {
+		return fmt.Errorf("setgid: %w", err)
+	}
+	if err := syscall.Setuid(uid); err != nil {
+		return fmt.Errorf("setuid: %w", err)
+	}
+
+	// Regaining root must fail, otherwise the privileges were not fully dropped.
+	if uid != 0 && syscall.Setuid(0) == nil {
+		return fmt.Errorf("privileges can still be regained")
+	}
+	return nil
+}
@@ -0,0 +1,35 @@
+//go:build unix
+
+package graceful
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestDropPrivilegesUnknown(t *testing.T) {
+	if err := DropPrivileges("no-such-user-graceful", "")(context.Background()); err == nil ||
+		!strings.Contains(err.Error(), "unknown user") {
+		t.Fatalf("expected an unknown user error, got %v", err)
+	}
+
+	uid := strconv.Itoa(os.Getuid())
+	if err := DropPrivileges(uid, "no-such-group-graceful")(context.Background()); err == nil ||
+		!strings.Contains(err.Error(), "unknown group") {
+		t.Fatalf("expected an unknown group error, got %v", err)
+	}
+}
+
+func TestDropPrivilegesToRoot(t *testing.T) {
+	if os.Getuid() != 0 {
+		t.Skip("requires root")
+	}
+
+	// Switching from root to root leaves the test process unchanged.
+	if err := DropPrivileges("0", "0")(context.Background()); err != nil {
+		t.Fatalf("expected root to switch to root, got %v", err)
+	}
+}
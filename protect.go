@@ -0,0 +1,44 @@
+package graceful
+
+import (
+	"context"
+	"log/slog"
+	"os"
+)
+
+// Defers acting on shutdown signals until done is called, so a short non-reentrant operation (ie writing a checkpoint
+// file) is not interrupted halfway. A signal received meanwhile is not lost: it is acted on as soon as every protected
+// section has ended. done is also called when ctx is done, and is safe to call more than once.
+//
+// Unlike Inhibit(), the wait is not bounded and only applies to signals, so keep protected sections short. Shutdown
+// triggered by other means (ie Shutdown()) is not deferred.
+//
+//	done := graceful.Protect(ctx)
+//	err := writeCheckpoint()
+//	done()
+func Protect(ctx context.Context) (done func()) {
+	return defaultRunner.Protect(ctx)
+}
+
+// Same as the package-level Protect(), but scoped to this Runner.
+func (r *Runner) Protect(ctx context.Context) (done func()) {
+	return r.protected.acquire(ctx, "")
+}
+
+// Blocks until every protected section has ended, after sig was received.
+func (r *Runner) awaitProtected(config *config, sig os.Signal) {
+	active, released := r.protected.active()
+	if len(active) == 0 {
+		return
+	}
+
+	config.log(slog.LevelInfo, "signal deferred until protected sections end", slog.String("signal", sig.String()),
+		slog.Int("sections", len(active)))
+
+	start := config.now()
+	for len(active) > 0 {
+		<-released
+		active, released = r.protected.active()
+	}
+	config.log(slog.LevelInfo, "protected sections ended", slog.Duration("deferred", config.since(start)))
+}
@@ -0,0 +1,55 @@
+package graceful
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestProtect(t *testing.T) {
+	r := New()
+	term := testSignal("term")
+
+	run := func(ctx context.Context) error {
+		done := r.Protect(ctx)
+		r.Signal(term)
+
+		select {
+		case <-ctx.Done():
+			return errors.New("expected the signal to be deferred while protected")
+		case <-time.After(50 * time.Millisecond):
+		}
+
+		done()
+		<-ctx.Done()
+		return nil
+	}
+
+	er := r.Start(nil, nil, WithSignals([]os.Signal{term}), WithRun(run))
+	if er.OsSignal != term || er.HasErrors() {
+		t.Fatalf("expected shutdown by the deferred signal, got %v (%v)", er.OsSignal, er)
+	}
+}
+
+func TestProtectEndedByContext(t *testing.T) {
+	r := New()
+	ctx, cancel := context.WithCancel(context.Background())
+
+	done := r.Protect(ctx)
+	defer done()
+	cancel()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		active, _ := r.protected.active()
+		if len(active) == 0 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("expected the protected section to end with its context")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
@@ -0,0 +1,36 @@
+package graceful
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+)
+
+// Creates an empty file at path once every startup function has completed, and removes it as soon as shutdown begins
+// (before the shutdown delay), for orchestrators and health checkers which probe a file. Failing to create it is
+// reported in ExitReason.ErrStartup, and failing to remove it in ExitReason.ErrsShutdown. A file left behind by a
+// process which crashed is not removed. Default: no file.
+func WithReadyFile(path string) Option {
+	return func(config *config) error {
+		if path == "" {
+			return invalidOption("ready file path must not be empty")
+		}
+
+		return WithRegistration(
+			func(ctx context.Context) error {
+				if err := os.WriteFile(path, nil, 0o644); err != nil {
+					return fmt.Errorf("failed to create ready file: %w", err)
+				}
+				return nil
+			},
+			func(ctx context.Context) error {
+				if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+					return fmt.Errorf("failed to remove ready file: %w", err)
+				}
+				return nil
+			},
+		)(config)
+	}
+}
@@ -0,0 +1,40 @@
+package graceful
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestReadyFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ready")
+
+	existed := false
+	run := func(ctx context.Context) error {
+		_, err := os.Stat(path)
+		existed = err == nil
+		return nil
+	}
+
+	if er := New().Start(nil, nil, WithRun(run), WithReadyFile(path)); !er.Clean() {
+		t.Fatalf("expected a clean exit, got %v", er)
+	}
+	if !existed {
+		t.Fatal("expected the ready file to exist while running")
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatalf("expected the ready file to be removed, got %v", err)
+	}
+}
+
+func TestReadyFileNotCreatedOnStartupFailure(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ready")
+	fail := func(ctx context.Context) error { return errors.New("boom") }
+
+	New().Start([]Func{fail}, nil, WithReadyFile(path))
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatalf("expected no ready file, got %v", err)
+	}
+}
@@ -0,0 +1,66 @@
+package graceful
+
+import "context"
+
+type registration struct {
+	register   Func
+	deregister Func
+}
+
+// Registers the instance with a service registry (ie Consul or etcd) once every startup function has completed, and
+// deregisters it as the very first act of shutdown, before the shutdown delay, so the registry stops routing traffic
+// while it drains.
+//
+// A register failure is reported in ExitReason.ErrStartup (and the registrations which succeeded are undone). A
+// deregister failure is reported in ExitReason.ErrsShutdown. Registrations are undone in reverse order. Either function
+// may be nil. Deregistration is bounded by the shutdown timeout, and the time it takes is deducted from what the
+// shutdown functions get. Default: none.
+func WithRegistration(register Func, deregister Func) Option {
+	return func(config *config) error {
+		config.registrations = append(config.registrations, registration{register: register, deregister: deregister})
+		return nil
+	}
+}
+
+// Runs the register functions. If one fails, those which succeeded are deregistered.
+func (c *config) register(ctx context.Context) error {
+	for i, reg := range c.registrations {
+		if reg.register == nil {
+			continue
+		}
+		if err := reg.register(ctx); err != nil {
+			c.deregisterFirst(ctx, i)
+			return err
+		}
+	}
+	return nil
+}
+
+// Runs the deregister functions in reverse order, bounded by the shutdown timeout, and returns their errors.
+func (c *config) deregister(ctx context.Context) []error {
+	return c.deregisterFirst(ctx, len(c.registrations))
+}
+
+func (c *config) deregisterFirst(ctx context.Context, n int) []error {
+	if n == 0 {
+		return nil
+	}
+
+	ctx, cancel := context.WithoutCancel(ctx), context.CancelFunc(nop)
+	if c.shutdownTimeout > 0 {
+		ctx, cancel = c.withTimeoutCause(ctx, c.shutdownTimeout, ErrShutdownTimeout)
+	}
+	defer cancel()
+
+	var errs []error
+	for i := n - 1; i >= 0; i-- {
+		reg := c.registrations[i]
+		if reg.deregister == nil {
+			continue
+		}
+		if err := reg.deregister(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
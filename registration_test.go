@@ -0,0 +1,94 @@
+package graceful
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestRegistration(t *testing.T) {
+	var order []string
+	step := func(name string) Func {
+		return func(ctx context.Context) error {
+			order = append(order, name)
+			return nil
+		}
+	}
+
+	r := New()
+	r.Shutdown(nil)
+	er := r.Start([]Func{step("startup")}, []Func{step("shutdown")},
+		WithRegistration(step("register a"), step("deregister a")),
+		WithRegistration(step("register b"), step("deregister b")),
+		WithRegistration(nil, nil),
+	)
+
+	expected := []string{"startup", "register a", "register b", "deregister b", "deregister a", "shutdown"}
+	if er.ErrStartup != nil || !slices.Equal(order, expected) {
+		t.Fatalf("expected %v, got %v (%v)", expected, order, er.ErrStartup)
+	}
+}
+
+func TestRegistrationFailure(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	var deregistered []string
+	deregister := func(name string) Func {
+		return func(ctx context.Context) error {
+			deregistered = append(deregistered, name)
+			return nil
+		}
+	}
+	ok := func(ctx context.Context) error { return nil }
+	fail := func(ctx context.Context) error { return errBoom }
+
+	er := New().Start(nil, nil,
+		WithRegistration(ok, deregister("a")),
+		WithRegistration(fail, deregister("b")),
+		WithRegistration(ok, deregister("c")),
+	)
+
+	if !errors.Is(er.ErrStartup, errBoom) || !slices.Equal(deregistered, []string{"a"}) {
+		t.Fatalf("expected only the successful registration to be undone, got %v (%v)", deregistered, er.ErrStartup)
+	}
+}
+
+func TestDeregistrationError(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	r := New()
+	r.Shutdown(nil)
+	er := r.Start(nil, nil, WithRegistration(nil, func(ctx context.Context) error { return errBoom }))
+
+	if len(er.ErrsShutdown) != 1 || er.ErrsShutdown[0] != errBoom {
+		t.Fatalf("expected the deregister error in ErrsShutdown, got %v", er.ErrsShutdown)
+	}
+}
+
+func TestDeregistrationCountsAgainstShutdownTimeout(t *testing.T) {
+	const timeout = 200 * time.Millisecond
+
+	var deregStart time.Time
+	slow := func(ctx context.Context) error {
+		deregStart = time.Now()
+		time.Sleep(150 * time.Millisecond)
+		return nil
+	}
+
+	var deadline time.Time
+	shutdown := func(ctx context.Context) error {
+		deadline, _ = ctx.Deadline()
+		return nil
+	}
+
+	r := New()
+	r.Shutdown(nil)
+	r.Start(nil, []Func{shutdown}, WithShutdownTimeout(timeout), WithRegistration(nil, slow))
+
+	if deadline.IsZero() || deadline.Sub(deregStart) > timeout+50*time.Millisecond {
+		t.Fatalf("expected the shutdown deadline within %s of deregistration starting, got %s", timeout,
+			deadline.Sub(deregStart))
+	}
+}
@@ -0,0 +1,59 @@
+package graceful
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+)
+
+// Runs the reload functions registered with WithReloadSignal() whenever their signal is received, until ctx is done.
+// Reloads run one at a time. Errors are reported to the logger and event subscribers and never trigger shutdown. The
+// signals are trapped until the returned function is called.
+func (r *Runner) watchReload(ctx context.Context, config *config) (stop func()) {
+	if len(config.reloadFns) == 0 {
+		return nop
+	}
+
+	rlSig := make(chan os.Signal, 1)
+	for sig := range config.reloadFns {
+		r.notify(rlSig, sig)
+	}
+
+	go func() {
+		for {
+			select {
+			case sig := <-rlSig:
+				r.reload(ctx, config, sig)
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return func() {
+		r.stopNotify(rlSig)
+	}
+}
+
+func (r *Runner) reload(ctx context.Context, config *config, sig os.Signal) {
+	config.log(slog.LevelInfo, "reload started", slog.String("signal", sig.String()))
+	r.emit(Event{Type: EventReloadBegan, Signal: sig})
+
+	start := config.now()
+	var errs []error
+	for _, fn := range config.recoverPanics(config.reloadFns[sig]) {
+		if err := fn(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	err := errors.Join(errs...)
+	duration := config.since(start)
+
+	if err != nil {
+		config.log(slog.LevelError, "reload failed", slog.String("signal", sig.String()), slog.Duration("duration", duration), slog.Any("err", err))
+	} else {
+		config.log(slog.LevelInfo, "reload completed", slog.String("signal", sig.String()), slog.Duration("duration", duration))
+	}
+	r.emit(Event{Type: EventReloadCompleted, Signal: sig, Duration: duration, Err: err})
+}
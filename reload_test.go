@@ -0,0 +1,61 @@
+package graceful
+
+import (
+	"context"
+	"errors"
+	"os"
+	"slices"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestReloadSignal(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	reload := func(ctx context.Context) error {
+		return errBoom
+	}
+
+	r := New()
+	events := make(chan Event, 64)
+	r.Subscribe(events)
+	defer r.Unsubscribe(events)
+
+	var completed Event
+	run := func(ctx context.Context) error {
+		if err := signalSelf(syscall.SIGHUP); err != nil {
+			return err
+		}
+		for {
+			select {
+			case e := <-events:
+				if e.Type == EventReloadCompleted {
+					completed = e
+					return nil
+				}
+			case <-time.After(time.Second):
+				return errors.New("reload functions were not run")
+			}
+		}
+	}
+
+	er := r.Start(nil, nil, WithReloadSignal(syscall.SIGHUP, reload), WithRun(run))
+
+	if er.ErrRuntime != nil || er.OsSignal != nil {
+		t.Fatalf("expected the reload signal not to trigger shutdown, got %v", er)
+	}
+	if completed.Signal != syscall.SIGHUP || !errors.Is(completed.Err, errBoom) {
+		t.Fatalf("expected a failed reload to be reported, got %+v", completed)
+	}
+}
+
+func TestReloadSignalRemovedFromShutdownSignals(t *testing.T) {
+	config := &config{signals: []os.Signal{os.Interrupt, syscall.SIGTERM}}
+	if err := parseOptions(config, []Option{WithReloadSignal(syscall.SIGTERM)}); err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(config.signals, []os.Signal{os.Interrupt}) {
+		t.Fatalf("expected only os.Interrupt, got %v", config.signals)
+	}
+}
@@ -0,0 +1,87 @@
+package graceful
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// Returned by Use() when no resource was provided under the name, or it has a different type.
+var ErrNoResource = errors.New("resource not provided")
+
+type resourcesKey struct{}
+
+// Values produced by Provide(), shared by every function of a single lifecycle.
+type resources struct {
+	mu     sync.RWMutex
+	values map[string]any
+}
+
+// Returns a startup function which runs fn and stores its result under name, so later startup functions, run
+// functions, Go() functions, reload functions, and shutdown functions can retrieve it with Use() rather than through
+// package-level variables:
+//
+//	graceful.Start([]graceful.Func{
+//		graceful.Provide("db", func(ctx context.Context) (*sql.DB, error) { return sql.Open("pgx", dsn) }),
+//	}, []graceful.Func{
+//		func(ctx context.Context) error {
+//			db, err := graceful.Use[*sql.DB](ctx, "db")
+//			if err != nil {
+//				return err
+//			}
+//			return db.Close()
+//		},
+//	})
+//
+// The returned function fails if a resource was already provided under name, and returns ErrNoStartupContext if ctx was
+// not passed to a startup function.
+func Provide[T any](name string, fn func(ctx context.Context) (T, error)) Func {
+	return func(ctx context.Context) error {
+		rs, ok := ctx.Value(resourcesKey{}).(*resources)
+		if !ok {
+			return ErrNoStartupContext
+		}
+
+		v, err := fn(ctx)
+		if err != nil {
+			return err
+		}
+
+		rs.mu.Lock()
+		defer rs.mu.Unlock()
+
+		if _, ok := rs.values[name]; ok {
+			return fmt.Errorf("resource %q already provided", name)
+		}
+		rs.values[name] = v
+		return nil
+	}
+}
+
+// Returns the resource stored under name by Provide(), given the context passed to any function of the same lifecycle.
+// Returns an error wrapping ErrNoResource if it was not provided (or its startup function has not completed yet), or
+// it is not a T.
+func Use[T any](ctx context.Context, name string) (T, error) {
+	var zero T
+
+	rs, ok := ctx.Value(resourcesKey{}).(*resources)
+	if !ok {
+		return zero, fmt.Errorf("%w: %q", ErrNoResource, name)
+	}
+
+	rs.mu.RLock()
+	v, ok := rs.values[name]
+	rs.mu.RUnlock()
+
+	if !ok {
+		return zero, fmt.Errorf("%w: %q", ErrNoResource, name)
+	}
+
+	t, ok := v.(T)
+	if !ok {
+		return zero, fmt.Errorf("%w: %q is %T, not %v", ErrNoResource, name, v, reflect.TypeFor[T]())
+	}
+	return t, nil
+}
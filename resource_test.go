@@ -0,0 +1,70 @@
+package graceful
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestProvideUse(t *testing.T) {
+	type db struct{ name string }
+
+	var used *db
+	use := func(ctx context.Context) error {
+		var err error
+		used, err = Use[*db](ctx, "db")
+		return err
+	}
+
+	r := New()
+	r.Shutdown(nil)
+	er := r.Start([]Func{
+		Provide("db", func(ctx context.Context) (*db, error) { return &db{name: "primary"}, nil }),
+	}, []Func{use})
+
+	if er.ErrStartup != nil || len(er.ErrsShutdown) > 0 {
+		t.Fatalf("unexpected errors: %v, %v", er.ErrStartup, er.ErrsShutdown)
+	}
+	if used == nil || used.name != "primary" {
+		t.Fatalf("expected the shutdown function to use the provided resource, got %v", used)
+	}
+}
+
+func TestUseErrors(t *testing.T) {
+	check := func(ctx context.Context) error {
+		if _, err := Use[string](ctx, "missing"); !errors.Is(err, ErrNoResource) {
+			t.Errorf("missing: expected ErrNoResource, got %v", err)
+		}
+		if _, err := Use[string](ctx, "port"); !errors.Is(err, ErrNoResource) {
+			t.Errorf("wrong type: expected ErrNoResource, got %v", err)
+		}
+		return nil
+	}
+
+	r := New()
+	r.Shutdown(nil)
+	er := r.Start([]Func{
+		Provide("port", func(ctx context.Context) (int, error) { return 8080, nil }),
+		check,
+	}, nil)
+	if er.ErrStartup != nil {
+		t.Fatal(er.ErrStartup)
+	}
+
+	if _, err := Use[int](context.Background(), "port"); !errors.Is(err, ErrNoResource) {
+		t.Fatalf("expected ErrNoResource outside of a lifecycle, got %v", err)
+	}
+}
+
+func TestProvideDuplicate(t *testing.T) {
+	one := func(ctx context.Context) (int, error) { return 1, nil }
+
+	er := New().Start([]Func{Provide("n", one), Provide("n", one)}, nil)
+	if er.ErrStartup == nil {
+		t.Fatal("expected providing the same name twice to fail")
+	}
+
+	if err := Provide("n", one)(context.Background()); err != ErrNoStartupContext {
+		t.Fatalf("expected ErrNoStartupContext outside of a lifecycle, got %v", err)
+	}
+}
@@ -0,0 +1,78 @@
+package graceful
+
+import (
+	"context"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Runner owns the state of a single application lifecycle.
+//
+// The package-level functions (Start, Shutdown, etc) operate on a default Runner. Use New() when multiple independent
+// lifecycles must coexist in the same process, such as in tests or embedded applications.
+type Runner struct {
+	rte   chan shutdownRequest
+	state atomic.Int32
+
+	ctxMu     sync.Mutex
+	ctx       context.Context
+	ctxCancel context.CancelCauseFunc
+
+	goMu    sync.Mutex
+	goCtx   context.Context
+	goQueue []Func
+	goWg    sync.WaitGroup
+
+	subsMu sync.Mutex
+	subs   []chan<- Event
+
+	rtMu     sync.Mutex
+	rtErrs   []error
+	rtReport func(error)
+
+	sigMu    sync.Mutex
+	sigSubs  []sigSub
+	sigReset bool
+
+	inhibitors holdSet
+	protected  holdSet
+
+	pingMu sync.Mutex
+	pings  map[string]*atomic.Uint64
+
+	maintenance atomic.Bool
+
+	statsMu         sync.Mutex
+	readyAt         time.Time
+	startupDuration time.Duration
+	lastExit        *ExitReason
+
+	attMu     sync.Mutex
+	attCtx    context.Context
+	attConfig *config
+	attached  []*attachment
+	attClosed bool
+
+	childMu  sync.Mutex
+	children map[string]*child
+	child    bool // Started by Spawn(), so OS signals are left to the parent.
+}
+
+var (
+	defaultRunner = New()
+	osExit        = os.Exit
+)
+
+// Returns the Runner used by the package-level functions.
+func Default() *Runner {
+	return defaultRunner
+}
+
+// Creates a new Runner whose lifecycle is independent of the package-level functions and any other Runner.
+func New() *Runner {
+	return &Runner{
+		rte: make(chan shutdownRequest, 1),
+	}
+}
@@ -0,0 +1,21 @@
+package graceful
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestRunnersAreIndependent(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	r1, r2 := New(), New()
+	r1.Shutdown(errBoom)
+	r2.Shutdown(nil)
+
+	if er := r1.Start(nil, nil); !errors.Is(er.ErrRuntime, errBoom) {
+		t.Fatalf("expected the first Runner's error, got %v", er.ErrRuntime)
+	}
+	if er := r2.Start(nil, nil); er.ErrRuntime != nil {
+		t.Fatalf("expected the second Runner not to see the first's error, got %v", er.ErrRuntime)
+	}
+}
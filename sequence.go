@@ -0,0 +1,26 @@
+package graceful
+
+import "context"
+
+// Provides convenience wrapper to run multiple Funcs in order within a single step of Start().
+//
+// Combined with Multi(), this expresses dependencies without wrapper closures. For example, to run a and b
+// concurrently and then c:
+//
+//	graceful.Sequence(graceful.Multi(a, b), c)
+//
+// Stops at the first error, which is attributed to "sequence[i]" unless the function was wrapped with Named(). If the
+// context is done before a function starts, its error is returned and the remaining functions are not run.
+func Sequence(fns ...Func) Func {
+	return func(ctx context.Context) error {
+		for i, fn := range fns {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			if err := fn(ctx); err != nil {
+				return attribute("sequence", i, err)
+			}
+		}
+		return nil
+	}
+}
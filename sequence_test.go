@@ -0,0 +1,48 @@
+package graceful
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestSequenceStopsAtFirstError(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	ran := false
+	err := Sequence(
+		func(ctx context.Context) error { return nil },
+		func(ctx context.Context) error { return errBoom },
+		func(ctx context.Context) error { ran = true; return nil },
+	)(context.Background())
+
+	if !errors.Is(err, errBoom) || ran {
+		t.Fatalf("expected the sequence to stop at the error, got %v", err)
+	}
+	if name := FuncName(err); name != "sequence[1]" {
+		t.Fatalf("expected the error to be attributed to sequence[1], got %q", name)
+	}
+}
+
+func TestSequenceKeepsNamedFunctions(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	err := Sequence(Named("db", func(ctx context.Context) error { return errBoom }))(context.Background())
+	if name := FuncName(err); name != "db" {
+		t.Fatalf("expected the error to be attributed to db, got %q", name)
+	}
+}
+
+func TestSequenceStopsWhenContextDone(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	ran := false
+	err := Sequence(
+		func(ctx context.Context) error { cancel(); return nil },
+		func(ctx context.Context) error { ran = true; return nil },
+	)(ctx)
+
+	if !errors.Is(err, context.Canceled) || ran {
+		t.Fatalf("expected the sequence to stop once the context is done, got %v", err)
+	}
+}
@@ -1,13 +1,74 @@
 package graceful
 
-// Signals that the application should exit. Passes the provided error, which can be nil, to unblock Run().
+// Signals that the application should exit. Passes the provided error, which can be nil, to unblock Start().
 //
-// Only the first error passed to Shutdown() will be propogated. It is safe to call concurrently.
+// Only the first error passed to Shutdown() will be propogated, unless WithAllRuntimeErrors() is used. It is safe to
+// call concurrently.
 //
 // This function should be called by scripts that have completed successfully (with nil) or applications that have an encountered an error requiring shutdown (with a non-nil error).
 func Shutdown(err error) {
+	defaultRunner.Shutdown(err)
+}
+
+// Same as the package-level Shutdown(), but scoped to this Runner.
+func (r *Runner) Shutdown(err error) {
+	r.ShutdownWithCause(err, "")
+}
+
+// Same as Shutdown(), but also records reason (ie "job finished" or "lost database connection") in
+// ExitReason.Reason, so programmatic exits can be told apart. The reason of the first call wins, along with its error.
+func ShutdownWithCause(err error, reason string) {
+	defaultRunner.ShutdownWithCause(err, reason)
+}
+
+// Same as the package-level ShutdownWithCause(), but scoped to this Runner.
+func (r *Runner) ShutdownWithCause(err error, reason string) {
+	r.recordRuntime(err)
+
 	select {
-	case rte <- err:
+	case r.rte <- shutdownRequest{err: err, reason: reason}:
 	default:
 	}
 }
+
+// Signals that the application has finished its work and should exit cleanly. Same as
+// ShutdownWithCause(nil, "stopped").
+func Stop() {
+	defaultRunner.Stop()
+}
+
+// Same as the package-level Stop(), but scoped to this Runner.
+func (r *Runner) Stop() {
+	r.ShutdownWithCause(nil, "stopped")
+}
+
+type shutdownRequest struct {
+	err    error
+	reason string
+}
+
+// Records a non-nil runtime error for ExitReason.ErrsRuntime.
+func (r *Runner) recordRuntime(err error) {
+	if err == nil {
+		return
+	}
+
+	r.rtMu.Lock()
+	r.rtErrs = append(r.rtErrs, err)
+	report := r.rtReport
+	r.rtMu.Unlock()
+
+	if report != nil {
+		report(err)
+	}
+}
+
+// Returns the runtime errors recorded so far and starts recording anew.
+func (r *Runner) takeRuntime() []error {
+	r.rtMu.Lock()
+	defer r.rtMu.Unlock()
+
+	errs := r.rtErrs
+	r.rtErrs = nil
+	return errs
+}
@@ -0,0 +1,30 @@
+package graceful
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestStop(t *testing.T) {
+	r := New()
+	r.Stop()
+
+	er := r.Start(nil, nil)
+	if !er.Clean() || er.Reason != "stopped" {
+		t.Fatalf("expected a clean exit with reason %q, got %v", "stopped", er)
+	}
+}
+
+func TestShutdownWithCause(t *testing.T) {
+	errLost := errors.New("lost connection")
+	r := New()
+
+	// The first request wins, along with its error.
+	r.ShutdownWithCause(errLost, "database unavailable")
+	r.ShutdownWithCause(nil, "job finished")
+
+	er := r.Start(nil, nil)
+	if er.ErrRuntime != errLost || er.Reason != "database unavailable" {
+		t.Fatalf("expected the first request, got %v (%q)", er.ErrRuntime, er.Reason)
+	}
+}
@@ -0,0 +1,150 @@
+package graceful
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/signal"
+	"slices"
+)
+
+// Delivers sig to the default Runner as if it were received from the OS. See Runner.Signal().
+func Signal(sig os.Signal) {
+	defaultRunner.Signal(sig)
+}
+
+// Delivers sig to this Runner as if it were received from the OS: it triggers shutdown, a reload, or a forced exit
+// according to the options, and is ignored if nothing is waiting for it. Useful for testing without sending real
+// signals to the process, and for platforms where shutdown is requested through other means (ie a Windows service).
+func (r *Runner) Signal(sig os.Signal) {
+	r.sigMu.Lock()
+	defer r.sigMu.Unlock()
+
+	for _, sub := range r.sigSubs {
+		if slices.Contains(sub.sigs, sig) {
+			// Same as the os/signal package, a full channel drops the signal.
+			select {
+			case sub.ch <- sig:
+			default:
+			}
+		}
+	}
+}
+
+type sigSub struct {
+	ch   chan<- os.Signal
+	sigs []os.Signal
+}
+
+// Same as signal.Notify(), but ch also receives signals delivered with Signal().
+func (r *Runner) notify(ch chan<- os.Signal, sigs ...os.Signal) {
+	r.sigMu.Lock()
+	defer r.sigMu.Unlock()
+
+	if !r.child && !r.sigReset {
+		signal.Notify(ch, sigs...)
+	}
+	r.sigSubs = append(r.sigSubs, sigSub{ch: ch, sigs: sigs})
+}
+
+// Same as signal.NotifyContext(), but ctx is also canceled by a signal delivered with Signal(), and its cause is a
+// *SignalError so the signal can be identified with signalFrom(). Like signal.NotifyContext(), the signals stay trapped
+// after the first one until stop is called.
+func (r *Runner) notifyContext(parent context.Context, sigs ...os.Signal) (ctx context.Context, stop context.CancelFunc) {
+	ctx, cancel := context.WithCancelCause(parent)
+
+	ch := make(chan os.Signal, 1)
+	r.notify(ch, sigs...)
+
+	go func() {
+		select {
+		case sig := <-ch:
+			cancel(&SignalError{Signal: sig})
+		case <-ctx.Done():
+		}
+	}()
+
+	return ctx, func() {
+		r.stopNotify(ch)
+		cancel(context.Canceled)
+	}
+}
+
+// Returns the signal which canceled a context returned by notifyContext(), or nil.
+func signalFrom(ctx context.Context) os.Signal {
+	var se *SignalError
+	if errors.As(context.Cause(ctx), &se) {
+		return se.Signal
+	}
+	return nil
+}
+
+// Stops trapping the OS signals monitored by the default Runner. See Runner.ResetSignals().
+func ResetSignals() {
+	defaultRunner.ResetSignals()
+}
+
+// Stops trapping the OS signals monitored by the current lifecycle (shutdown, reload, maintenance, and kill signals),
+// so they get Go's default behavior again (ie SIGTERM terminates the process), or can be handed off to another handler
+// registered with signal.Notify(). Signals delivered with Signal() are still handled.
+//
+// Applies until Start() returns, so it may also be called before Start() to run a lifecycle which leaves OS signals to
+// the caller. Every signal is released once Start() returns regardless. See WithSignalReset() to reset once shutdown
+// begins.
+func (r *Runner) ResetSignals() {
+	r.sigMu.Lock()
+	defer r.sigMu.Unlock()
+
+	r.sigReset = true
+	for _, sub := range r.sigSubs {
+		signal.Stop(sub.ch)
+	}
+}
+
+// Resumes trapping OS signals for the next lifecycle.
+func (r *Runner) endSignalReset() {
+	r.sigMu.Lock()
+	defer r.sigMu.Unlock()
+
+	r.sigReset = false
+}
+
+// Same as signal.Stop(), for a channel registered with notify().
+func (r *Runner) stopNotify(ch chan<- os.Signal) {
+	signal.Stop(ch)
+
+	r.sigMu.Lock()
+	defer r.sigMu.Unlock()
+
+	r.sigSubs = slices.DeleteFunc(r.sigSubs, func(sub sigSub) bool {
+		return sub.ch == ch
+	})
+}
+
+// Blocks until one of signals is received, Shutdown() (or a variant) is called, or ctx is done, without running any
+// lifecycle functions. For programs which manage their own lifecycle but want the signal plumbing of this package.
+//
+// Returns the signal if one was received, otherwise the error passed to Shutdown() or the cause of ctx. Shutdown(nil)
+// returns (nil, nil). If no signals are given, the defaults are used: os.Interrupt, syscall.SIGINT, syscall.SIGTERM.
+func Wait(ctx context.Context, signals ...os.Signal) (os.Signal, error) {
+	return defaultRunner.Wait(ctx, signals...)
+}
+
+// Same as the package-level Wait(), but scoped to this Runner.
+func (r *Runner) Wait(ctx context.Context, signals ...os.Signal) (os.Signal, error) {
+	if len(signals) == 0 {
+		signals = defaultSignals()
+	}
+
+	sigCtx, sigStop := r.notifyContext(context.Background(), signals...)
+	defer sigStop()
+
+	select {
+	case <-sigCtx.Done():
+		return signalFrom(sigCtx), nil
+	case req := <-r.rte:
+		return nil, req.err
+	case <-ctx.Done():
+		return nil, context.Cause(ctx)
+	}
+}
@@ -0,0 +1,143 @@
+package graceful
+
+import (
+	"context"
+	"errors"
+	"os"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestWait(t *testing.T) {
+	r := New()
+	usr := testSignal("usr")
+
+	go func() {
+		// Wait() may not have subscribed yet, so keep signaling until it returns.
+		for range 100 {
+			r.Signal(usr)
+			time.Sleep(5 * time.Millisecond)
+		}
+	}()
+
+	if sig, err := r.Wait(context.Background(), usr); sig != usr || err != nil {
+		t.Fatalf("expected the signal, got %v (%v)", sig, err)
+	}
+}
+
+func TestWaitShutdown(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	r := New()
+	r.Shutdown(errBoom)
+	if sig, err := r.Wait(context.Background()); sig != nil || err != errBoom {
+		t.Fatalf("expected the error passed to Shutdown(), got %v (%v)", sig, err)
+	}
+
+	r.Shutdown(nil)
+	if sig, err := r.Wait(context.Background()); sig != nil || err != nil {
+		t.Fatalf("expected (nil, nil) after Shutdown(nil), got %v (%v)", sig, err)
+	}
+}
+
+func TestWaitContext(t *testing.T) {
+	errDone := errors.New("done")
+	ctx, cancel := context.WithCancelCause(context.Background())
+	cancel(errDone)
+
+	if sig, err := New().Wait(ctx); sig != nil || err != errDone {
+		t.Fatalf("expected the cause of ctx, got %v (%v)", sig, err)
+	}
+}
+
+func TestNotifyContext(t *testing.T) {
+	r := New()
+	term := testSignal("term")
+
+	ctx, stop := r.notifyContext(context.Background(), term)
+	defer stop()
+	r.Signal(term)
+
+	<-ctx.Done()
+	if sig := signalFrom(ctx); sig != term {
+		t.Fatalf("expected the signal to be the cause, got %v", sig)
+	}
+
+	ctx, stop = r.notifyContext(context.Background(), term)
+	stop()
+	if sig := signalFrom(ctx); ctx.Err() == nil || sig != nil {
+		t.Fatalf("expected stop to cancel the context without a signal, got %v (%v)", sig, ctx.Err())
+	}
+}
+
+// Returns how many subscriptions of r include sig.
+func subscribers(r *Runner, sig os.Signal) int {
+	r.sigMu.Lock()
+	defer r.sigMu.Unlock()
+
+	var n int
+	for _, sub := range r.sigSubs {
+		if slices.Contains(sub.sigs, sig) {
+			n++
+		}
+	}
+	return n
+}
+
+func TestSignalsReleasedAfterStart(t *testing.T) {
+	r := New()
+	term := testSignal("term")
+
+	er := r.Start(nil, nil, WithSignals([]os.Signal{term}), WithReloadSignal(testSignal("hup"), func(ctx context.Context) error { return nil }),
+		WithRun(func(ctx context.Context) error {
+			r.Signal(term)
+			<-ctx.Done()
+			return nil
+		}))
+	if er.OsSignal != term {
+		t.Fatalf("expected shutdown by the signal, got %v", er.OsSignal)
+	}
+	if len(r.sigSubs) != 0 {
+		t.Fatalf("expected every signal to be released once Start() returns, got %d subscriptions", len(r.sigSubs))
+	}
+}
+
+func TestSignalReset(t *testing.T) {
+	r := New()
+	term := testSignal("term")
+
+	var trapped int
+	shutdown := func(ctx context.Context) error {
+		trapped = subscribers(r, term)
+		return nil
+	}
+
+	r.Start(nil, []Func{shutdown}, WithSignals([]os.Signal{term}), WithSignalReset(), WithRun(func(ctx context.Context) error {
+		r.Signal(term)
+		<-ctx.Done()
+		return nil
+	}))
+	if trapped != 0 {
+		t.Fatalf("expected the shutdown signals to be released once shutdown began, got %d subscriptions", trapped)
+	}
+}
+
+func TestResetSignals(t *testing.T) {
+	r := New()
+	term := testSignal("term")
+
+	// Signals delivered with Signal() are still handled.
+	r.ResetSignals()
+	er := r.Start(nil, nil, WithSignals([]os.Signal{term}), WithRun(func(ctx context.Context) error {
+		r.Signal(term)
+		<-ctx.Done()
+		return nil
+	}))
+	if er.OsSignal != term {
+		t.Fatalf("expected shutdown by the signal, got %v", er.OsSignal)
+	}
+	if r.sigReset {
+		t.Fatal("expected the reset to end with the lifecycle")
+	}
+}
@@ -0,0 +1,19 @@
+//go:build !plan9
+
+package graceful
+
+import (
+	"os"
+	"syscall"
+)
+
+// Returns the number of sig, if it has one.
+func signalNumber(sig os.Signal) (int, bool) {
+	s, ok := sig.(syscall.Signal)
+	return int(s), ok
+}
+
+// Returns the signal numbered n, or nil if signals are not numbered on this platform.
+func signalFromNumber(n int) os.Signal {
+	return syscall.Signal(n)
+}
@@ -0,0 +1,13 @@
+package graceful
+
+import "os"
+
+// Plan 9 notes are strings, so signals have no number.
+func signalNumber(sig os.Signal) (int, bool) {
+	return 0, false
+}
+
+// Returns the signal numbered n, or nil if signals are not numbered on this platform.
+func signalFromNumber(n int) os.Signal {
+	return nil
+}
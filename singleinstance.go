@@ -0,0 +1,60 @@
+package graceful
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// Returned by the startup function of SingleInstance() when another process holds the lock.
+var ErrAlreadyRunning = errors.New("another instance is already running")
+
+// Returns startup and shutdown functions which hold an exclusive lock for the lifetime of the application, so only one
+// instance runs at a time. The startup function fails with an error matching ErrAlreadyRunning if another process holds
+// the lock. The lock is released by the shutdown function, and by the OS if the process dies.
+//
+// name is either a path to the lock file, or a plain name, which is locked as name + ".lock" in os.TempDir(). The file
+// is left in place after the lock is released. Supported on Linux, the BSDs, macOS, and Windows; elsewhere the startup
+// function fails with an error matching errors.ErrUnsupported.
+func SingleInstance(name string) (startup Func, shutdown Func) {
+	path := name
+	if !strings.ContainsRune(name, os.PathSeparator) && !strings.ContainsRune(name, '/') {
+		path = filepath.Join(os.TempDir(), name+".lock")
+	}
+
+	var (
+		mu     sync.Mutex
+		unlock func() error
+	)
+
+	startup = func(ctx context.Context) error {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if unlock != nil {
+			return nil
+		}
+
+		var err error
+		unlock, err = lockFile(path)
+		return err
+	}
+
+	shutdown = func(ctx context.Context) error {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if unlock == nil {
+			return nil
+		}
+
+		err := unlock()
+		unlock = nil
+		return err
+	}
+
+	return startup, shutdown
+}
@@ -0,0 +1,41 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd || dragonfly
+
+package graceful
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// Locks path with flock(2), writing this process's PID to it so the holder can be identified.
+func lockFile(path string) (unlock func() error, err error) {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := syscall.Flock(int(f.Fd()), syscall.LOCK_EX|syscall.LOCK_NB); err != nil {
+		defer f.Close()
+
+		if errors.Is(err, syscall.EWOULDBLOCK) {
+			if b, _ := os.ReadFile(path); len(b) > 0 {
+				return nil, fmt.Errorf("%w (pid %s, lock %s)", ErrAlreadyRunning, strings.TrimSpace(string(b)), path)
+			}
+			return nil, fmt.Errorf("%w (lock %s)", ErrAlreadyRunning, path)
+		}
+		return nil, err
+	}
+
+	if err := f.Truncate(0); err == nil {
+		f.WriteAt([]byte(strconv.Itoa(os.Getpid())+"\n"), 0)
+	}
+
+	return func() error {
+		defer f.Close()
+		return syscall.Flock(int(f.Fd()), syscall.LOCK_UN)
+	}, nil
+}
@@ -0,0 +1,12 @@
+//go:build !(linux || darwin || freebsd || netbsd || openbsd || dragonfly || windows)
+
+package graceful
+
+import (
+	"errors"
+	"fmt"
+)
+
+func lockFile(path string) (unlock func() error, err error) {
+	return nil, fmt.Errorf("single instance lock is not supported on this platform: %w", errors.ErrUnsupported)
+}
@@ -0,0 +1,40 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd || dragonfly || windows
+
+package graceful
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+func TestSingleInstance(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.lock")
+	ctx := context.Background()
+
+	startA, stopA := SingleInstance(path)
+	startB, stopB := SingleInstance(path)
+
+	if err := startA(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := startB(ctx); !errors.Is(err, ErrAlreadyRunning) {
+		t.Fatalf("expected ErrAlreadyRunning while the lock is held, got %v", err)
+	}
+
+	if err := stopA(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := startB(ctx); err != nil {
+		t.Fatalf("expected the lock to be acquired once released, got %v", err)
+	}
+	if err := stopB(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	// Releasing twice is harmless.
+	if err := stopB(ctx); err != nil {
+		t.Fatalf("expected a second release to do nothing, got %v", err)
+	}
+}
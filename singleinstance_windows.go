@@ -0,0 +1,31 @@
+//go:build windows
+
+package graceful
+
+import (
+	"errors"
+	"fmt"
+	"syscall"
+)
+
+// Opens path without sharing, which fails for every other process until the handle is closed.
+func lockFile(path string) (unlock func() error, err error) {
+	p, err := syscall.UTF16PtrFromString(path)
+	if err != nil {
+		return nil, err
+	}
+
+	h, err := syscall.CreateFile(p, syscall.GENERIC_READ|syscall.GENERIC_WRITE, 0, nil, syscall.OPEN_ALWAYS,
+		syscall.FILE_ATTRIBUTE_NORMAL, 0)
+	if err != nil {
+		// ERROR_SHARING_VIOLATION
+		if errors.Is(err, syscall.Errno(32)) {
+			return nil, fmt.Errorf("%w (lock %s)", ErrAlreadyRunning, path)
+		}
+		return nil, err
+	}
+
+	return func() error {
+		return syscall.CloseHandle(h)
+	}, nil
+}
@@ -0,0 +1,24 @@
+package graceful
+
+import (
+	"context"
+	"database/sql"
+)
+
+// Adapts db to the lifecycle.
+//
+// startup pings db with the startup context if pingOnStart is true, otherwise it does nothing. shutdown closes the pool.
+func SQLDB(db *sql.DB, pingOnStart bool) (startup Func, shutdown Func) {
+	startup = func(ctx context.Context) error {
+		if !pingOnStart {
+			return nil
+		}
+		return db.PingContext(ctx)
+	}
+
+	shutdown = func(ctx context.Context) error {
+		return db.Close()
+	}
+
+	return startup, shutdown
+}
@@ -0,0 +1,67 @@
+package graceful
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"testing"
+)
+
+type fakeConnector struct {
+	pingErr error
+	pings   int
+}
+
+func (fc *fakeConnector) Connect(context.Context) (driver.Conn, error) { return &fakeConn{fc: fc}, nil }
+
+func (fc *fakeConnector) Driver() driver.Driver { return nil }
+
+type fakeConn struct {
+	fc *fakeConnector
+}
+
+func (c *fakeConn) Prepare(string) (driver.Stmt, error) { return nil, errors.New("not supported") }
+
+func (c *fakeConn) Close() error { return nil }
+
+func (c *fakeConn) Begin() (driver.Tx, error) { return nil, errors.New("not supported") }
+
+func (c *fakeConn) Ping(context.Context) error {
+	c.fc.pings++
+	return c.fc.pingErr
+}
+
+func TestSQLDB(t *testing.T) {
+	fc := &fakeConnector{}
+	db := sql.OpenDB(fc)
+	startup, shutdown := SQLDB(db, true)
+
+	r := New()
+	r.Shutdown(nil)
+	er := r.Start([]Func{startup}, []Func{shutdown})
+	if er.ErrStartup != nil || len(er.ErrsShutdown) != 0 || fc.pings != 1 {
+		t.Fatalf("expected a single ping and a clean exit, got %d pings and %v", fc.pings, er)
+	}
+	if err := db.Ping(); err == nil {
+		t.Fatal("expected the pool to be closed")
+	}
+}
+
+func TestSQLDBPingFailure(t *testing.T) {
+	errBoom := errors.New("unreachable")
+	startup, _ := SQLDB(sql.OpenDB(&fakeConnector{pingErr: errBoom}), true)
+
+	if err := startup(context.Background()); !errors.Is(err, errBoom) {
+		t.Fatalf("expected the ping error, got %v", err)
+	}
+}
+
+func TestSQLDBWithoutPing(t *testing.T) {
+	fc := &fakeConnector{}
+	startup, _ := SQLDB(sql.OpenDB(fc), false)
+
+	if err := startup(context.Background()); err != nil || fc.pings != 0 {
+		t.Fatalf("expected no ping, got %d pings and %v", fc.pings, err)
+	}
+}
@@ -3,10 +3,17 @@ package graceful
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
 	"os"
-	"os/signal"
+	"slices"
+	"strings"
+	"sync"
 	"syscall"
 	"time"
+
+	"go.opentelemetry.io/otel/trace"
 )
 
 // Contains information about why the program exited.
@@ -14,14 +21,155 @@ type ExitReason struct {
 	OsSignal     os.Signal
 	ErrStartup   error
 	ErrRuntime   error
-	ErrsShutdown []error
+	ErrsRuntime  []error // Every runtime error, including ErrRuntime. Only set if WithAllRuntimeErrors() was provided.
+	ErrContext   error
+	Reason       string  // Given to ShutdownWithCause() or Stop().
+	ErrsShutdown []error // Each identifies its function with a *ShutdownError, except timeouts waiting for run functions.
+	Timing       Timing
+
+	// Errors of startup and shutdown functions wrapped with BestEffort(). They do not affect ExitCode().
+	WarningsStartup  []error
+	WarningsShutdown []error
+
+	// Number of times Supervise() restarted the lifecycle before this exit.
+	Restarts int
+
+	// Set if shutdown was requested with Shutdown() (or a variant), so Supervise() can tell Shutdown(nil) and Stop() from
+	// a run function returning.
+	requested bool
+
+	// Stack traces of all goroutines at the moment the shutdown timeout elapsed. See WithGoroutineDumpOnTimeout().
+	GoroutineDump []byte
+
+	// Shutdown functions left running in the background after the shutdown timeout. See WithBackgroundShutdown().
+	Overrun *Overrun
+
+	// Exit reasons of the children started with Spawn() which were stopped by this lifecycle's shutdown, keyed by name.
+	// Their errors do not affect ExitCode().
+	Children map[string]*ExitReason
+}
+
+// Contains how long parts of the lifecycle took.
+type Timing struct {
+	ShutdownDelay time.Duration
+
+	// How long shutdown waited for the inhibitors acquired with Inhibit() to be released.
+	Inhibited time.Duration
+
+	// Every startup, rollback, and shutdown function which returned, in the order they returned.
+	Steps []StepTiming
+}
+
+// Contains how long a single lifecycle function took.
+type StepTiming struct {
+	Phase    string // "startup", "rollback", or "shutdown"
+	Index    int
+	Name     string // Set if the function was wrapped with Named().
+	Start    time.Time
+	Duration time.Duration
+	Err      error
 }
 
 type ExitReasonPrintable struct {
-	OsSignal     string   `json:"osSignal"`
-	ErrStartup   string   `json:"errStartup"`
-	ErrRuntime   string   `json:"errRuntime"`
-	ErrsShutdown []string `json:"errsShutdown"`
+	OsSignal     string          `json:"osSignal"`
+	ErrStartup   string          `json:"errStartup"`
+	ErrRuntime   string          `json:"errRuntime"`
+	ErrsRuntime  []string        `json:"errsRuntime"`
+	ErrContext   string          `json:"errContext"`
+	Reason       string          `json:"reason,omitempty"`
+	ErrsShutdown []string        `json:"errsShutdown"`
+	Timing       TimingPrintable `json:"timing"`
+
+	WarningsStartup  []string `json:"warningsStartup,omitempty"`
+	WarningsShutdown []string `json:"warningsShutdown,omitempty"`
+
+	Restarts      int    `json:"restarts"`
+	GoroutineDump string `json:"goroutineDump,omitempty"`
+
+	Children map[string]*ExitReasonPrintable `json:"children,omitempty"`
+}
+
+type TimingPrintable struct {
+	ShutdownDelay string                `json:"shutdownDelay"`
+	Inhibited     string                `json:"inhibited,omitempty"`
+	Steps         []StepTimingPrintable `json:"steps"`
+}
+
+type StepTimingPrintable struct {
+	Phase    string `json:"phase"`
+	Index    int    `json:"index"`
+	Name     string `json:"name"`
+	Start    string `json:"start"`
+	Duration string `json:"duration"`
+	Err      string `json:"err"`
+}
+
+// Describes every cause recorded in the struct, or reports a clean exit if there are none.
+func (er *ExitReason) Error() string {
+	var msgs []string
+
+	if er.ErrStartup != nil {
+		msgs = append(msgs, "startup: "+er.ErrStartup.Error())
+	}
+
+	if er.OsSignal != nil {
+		msgs = append(msgs, "signal: "+er.OsSignal.String())
+	}
+
+	if len(er.ErrsRuntime) > 0 {
+		for _, e := range er.ErrsRuntime {
+			msgs = append(msgs, "runtime: "+e.Error())
+		}
+	} else if er.ErrRuntime != nil {
+		msgs = append(msgs, "runtime: "+er.ErrRuntime.Error())
+	}
+
+	if er.ErrContext != nil {
+		msgs = append(msgs, "context: "+er.ErrContext.Error())
+	}
+
+	for _, e := range er.ErrsShutdown {
+		msgs = append(msgs, "shutdown: "+e.Error())
+	}
+
+	if len(msgs) == 0 {
+		if er.Reason != "" {
+			return "exited cleanly: " + er.Reason
+		}
+		return "exited cleanly"
+	}
+
+	if er.Reason != "" {
+		msgs = append(msgs, "reason: "+er.Reason)
+	}
+
+	return strings.Join(msgs, "; ")
+}
+
+// Returns every non-nil error in the struct so that errors.Is() and errors.As() can inspect them. An OS signal is
+// returned as a *SignalError.
+func (er *ExitReason) Unwrap() []error {
+	var errs []error
+
+	if er.ErrStartup != nil {
+		errs = append(errs, er.ErrStartup)
+	}
+
+	if er.OsSignal != nil {
+		errs = append(errs, &SignalError{Signal: er.OsSignal})
+	}
+
+	if len(er.ErrsRuntime) > 0 {
+		errs = append(errs, er.ErrsRuntime...)
+	} else if er.ErrRuntime != nil {
+		errs = append(errs, er.ErrRuntime)
+	}
+
+	if er.ErrContext != nil {
+		errs = append(errs, er.ErrContext)
+	}
+
+	return append(errs, er.ErrsShutdown...)
 }
 
 func (er *ExitReason) ToPrintable() *ExitReasonPrintable {
@@ -39,48 +187,137 @@ func (er *ExitReason) ToPrintable() *ExitReasonPrintable {
 		erp.ErrRuntime = er.ErrRuntime.Error()
 	}
 
+	for _, e := range er.ErrsRuntime {
+		erp.ErrsRuntime = append(erp.ErrsRuntime, e.Error())
+	}
+
+	if er.ErrContext != nil {
+		erp.ErrContext = er.ErrContext.Error()
+	}
+
+	erp.Reason = er.Reason
+
 	for _, e := range er.ErrsShutdown {
 		erp.ErrsShutdown = append(erp.ErrsShutdown, e.Error())
 	}
 
+	for _, e := range er.WarningsStartup {
+		erp.WarningsStartup = append(erp.WarningsStartup, e.Error())
+	}
+
+	for _, e := range er.WarningsShutdown {
+		erp.WarningsShutdown = append(erp.WarningsShutdown, e.Error())
+	}
+
+	erp.Timing.ShutdownDelay = er.Timing.ShutdownDelay.String()
+	if er.Timing.Inhibited > 0 {
+		erp.Timing.Inhibited = er.Timing.Inhibited.String()
+	}
+	erp.Restarts = er.Restarts
+	erp.GoroutineDump = string(er.GoroutineDump)
+
+	for _, st := range er.Timing.Steps {
+		stp := StepTimingPrintable{
+			Phase:    st.Phase,
+			Index:    st.Index,
+			Name:     st.Name,
+			Start:    st.Start.Format(time.RFC3339Nano),
+			Duration: st.Duration.String(),
+		}
+		if st.Err != nil {
+			stp.Err = st.Err.Error()
+		}
+		erp.Timing.Steps = append(erp.Timing.Steps, stp)
+	}
+
+	for name, child := range er.Children {
+		if erp.Children == nil {
+			erp.Children = map[string]*ExitReasonPrintable{}
+		}
+		erp.Children[name] = child.ToPrintable()
+	}
+
 	return erp
 }
 
 // Marshals the struct.
 func (er *ExitReason) MarshalStr() string {
-	bs, _ := json.Marshal(er.ToPrintable())
-	return string(bs)
+	s, _ := er.MarshalStrErr()
+	return s
 }
 
 // Marshals the struct with indents.
 // prefix is usually "" and indent is usually "\t"
 func (er *ExitReason) MarshalIndentStr(prefix string, indent string) string {
-	bs, _ := json.MarshalIndent(er.ToPrintable(), prefix, indent)
-	return string(bs)
+	s, _ := er.MarshalIndentStrErr(prefix, indent)
+	return s
 }
 
-type Func func(ctx context.Context) error
-
-type option struct {
-	code  int
-	value any
+// Same as MarshalStr(), but returns the marshaling error instead of an empty or partial string.
+func (er *ExitReason) MarshalStrErr() (string, error) {
+	bs, err := json.Marshal(er.ToPrintable())
+	return string(bs), err
 }
 
-type config struct {
-	shutdownTimeout time.Duration
-	startupTimeout  time.Duration
-	signals         []os.Signal
+// Same as MarshalIndentStr(), but returns the marshaling error instead of an empty or partial string.
+func (er *ExitReason) MarshalIndentStrErr(prefix string, indent string) (string, error) {
+	bs, err := json.MarshalIndent(er.ToPrintable(), prefix, indent)
+	return string(bs), err
 }
 
-const (
-	optionStartupTimeout  = 1
-	optionShutdownTimeout = 2
-	optionSignals         = 10
-)
+type Func func(ctx context.Context) error
 
-var (
-	rte = make(chan error, 1)
-)
+type config struct {
+	runner              *Runner
+	shutdownTimeout     time.Duration
+	startupTimeout      time.Duration
+	signals             []os.Signal
+	rollbackFns         []Func
+	runFns              []Func
+	exitWriter          io.Writer
+	logger              *slog.Logger
+	tracer              trace.Tracer
+	emit                func(Event)
+	shutdownDelay       time.Duration
+	forceExit           bool
+	forceExitCode       int
+	perShutdownTimeout  time.Duration
+	shutdownConcurrency int
+	panicRecovery       bool
+	steps               *stepRecorder
+	reloadFns           map[os.Signal][]Func
+	policies            map[os.Signal]Policy
+	dumpOnTimeout       bool
+	dumpWriter          io.Writer
+	initMode            bool
+	restartPolicy       RestartPolicy
+	maxRetries          int
+	restartBackoff      time.Duration
+	allRuntimeErrs      bool
+	clock               Clock
+	readyFns            []func()
+	backgroundShutdown  time.Duration
+	registrations       []registration
+	maxInhibit          time.Duration
+	killSignal          os.Signal
+	watchdogInterval    time.Duration
+	watchdogTimeout     time.Duration
+	guardInterval       time.Duration
+	guardLimits         ResourceLimits
+	durationsSet        map[string]time.Duration
+	defaulting          bool
+	progress            func(completed, total int, name string, d time.Duration)
+	chaos               *chaosState
+	maintenanceSig      os.Signal
+	maintenanceEnter    Func
+	maintenanceExit     Func
+	exitReasonFile      string
+	errorReporter       func(phase Phase, err error)
+	errorFlush          Func
+	budget              bool
+	budgetWeights       []float64
+	signalReset         bool
+}
 
 // Helps run an application by handling graceful startup and shutdown.
 //
@@ -90,91 +327,553 @@ var (
 //
 // 1. Run startup functions sequentially.
 //   - If any of these functions returns an error, this function will return immediately.
+//   - If a specified OS signal is received, the startup context is canceled and this function will return immediately
+//     with ExitReason.OsSignal set.
 //
-// 2. Blocks until a runtime signal (from Shutdown()) or specified OS signal (ie ctrl+c) is received.
+// 2. Blocks until a runtime signal (from Shutdown()), specified OS signal (ie ctrl+c), or parent context cancellation is received.
 //   - Only the first runtime error received (if any) will be returned. All others are discarded.
 //   - Default signals monitored are os.Interrupt, syscall.SIGINT, and syscall.SIGTERM.
+//   - Run functions provided with WithRun() are launched concurrently. The first to return (with or without an error)
+//     triggers shutdown and its error is reported in ExitReason.ErrRuntime.
+//   - Goroutines launched with Go() are started. The first to return a non-nil error triggers shutdown.
+//   - Signals provided with WithReloadSignal() run their reload functions without shutting down.
 //
-// 3. Run the shutdown functions sequentially.
-func Start(startupFns []Func, shutdownFns []Func, opts ...*option) *ExitReason {
-	er := &ExitReason{}
-	fnErrs := make(chan error, 1)
+// 3. Run the shutdown functions sequentially (or concurrently with WithConcurrentShutdown()).
+//   - The context passed to run functions and Go() goroutines is canceled before the shutdown functions are run. They
+//     are waited on after the shutdown functions return, so they may rely on a shutdown function to stop them (ie http.Server).
+//
+// If startup fails or is aborted and WithStartupRollback() was provided, the rollback functions of the completed startup
+// functions are run in reverse order before returning, followed by any registered with OnShutdown(). Their errors are
+// reported in ExitReason.ErrsShutdown.
+//
+// Start uses the package's default Runner. See New() for running independent lifecycles.
+func Start(startupFns []Func, shutdownFns []Func, opts ...Option) *ExitReason {
+	return defaultRunner.Start(startupFns, shutdownFns, opts...)
+}
 
+// Same as Start(), but startup functions receive a context derived from ctx and cancellation of ctx triggers shutdown.
+//
+// If ctx is canceled after startup, its cause is reported in ExitReason.ErrContext (or ExitReason.ErrStartup if canceled
+// during startup). Shutdown functions receive a context which retains the values of ctx but is not canceled along with
+// it.
+func StartContext(ctx context.Context, startupFns []Func, shutdownFns []Func, opts ...Option) *ExitReason {
+	return defaultRunner.StartContext(ctx, startupFns, shutdownFns, opts...)
+}
+
+// Same as the package-level Start(), but scoped to this Runner.
+func (r *Runner) Start(startupFns []Func, shutdownFns []Func, opts ...Option) *ExitReason {
+	return r.StartContext(context.Background(), startupFns, shutdownFns, opts...)
+}
+
+// Same as the package-level StartContext(), but scoped to this Runner.
+func (r *Runner) StartContext(ctx context.Context, startupFns []Func, shutdownFns []Func, opts ...Option) *ExitReason {
+	er, _ := r.start(ctx, startupFns, shutdownFns, opts)
+	return er
+}
+
+// Runs a lifecycle. Also returns the configuration parsed from opts, so callers such as Run() need not parse them again.
+func (r *Runner) start(ctx context.Context, startupFns []Func, shutdownFns []Func, opts []Option) (*ExitReason, *config) {
+	defer r.resetGo()
+	defer r.resetAttach()
+	defer r.endSignalReset()
+	r.prepareContext()
+
+	// Every function of this lifecycle derives its context from ctx, so resources provided during startup are visible
+	// to all of them.
+	ctx = context.WithValue(ctx, resourcesKey{}, &resources{values: map[string]any{}})
+
+	er := &ExitReason{}
 	config := &config{
-		signals: []os.Signal{os.Interrupt, syscall.SIGINT, syscall.SIGTERM},
+		runner:     r,
+		signals:    defaultSignals(),
+		emit:       r.emit,
+		steps:      &stepRecorder{},
+		maxInhibit: defaultMaxInhibit,
 	}
+
+	r.setState(StateStarting)
+	defer func() {
+		er.Timing.Steps = config.steps.close()
+		if errs := r.takeRuntime(); config.allRuntimeErrs {
+			er.ErrsRuntime = errs
+		}
+		writeExitReasonFile(config, er)
+		r.setExited(er)
+		r.setState(StateStopped)
+		r.emit(Event{Type: EventExited, ExitReason: er})
+	}()
+
 	if err := parseOptions(config, opts); err != nil {
 		er.ErrStartup = err
-		return er
+		r.cancelContext(er)
+		return er, config
+	}
+	if config.clock != nil {
+		ctx = context.WithValue(ctx, clockKey{}, config.clock)
 	}
+	r.loadLastExit(config)
+	startedAt := config.now()
+	defer config.flushErrs(ctx)
+	defer r.reportRuntime(config)()
+
+	if config.initMode {
+		defer startReaper()()
+	}
+
+	// Monitor the OS so that a signal received during startup can abort it.
+	sigCtx, sigStop := r.notifyContext(context.Background(), config.signals...)
+	defer sigStop()
 
 	// Start the application and exit early if any errors occur.
-	stCtx, stCancel := context.Background(), nop
+	stCtx, stCancel := context.WithCancel(ctx)
 	if config.startupTimeout > 0 {
-		stCtx, stCancel = context.WithTimeout(stCtx, config.startupTimeout)
+		stCancel()
+		stCtx, stCancel = config.withTimeoutCause(ctx, config.startupTimeout, ErrStartupTimeout)
 	}
-
+	hooks := &hookRegistry{}
+	stCtx = context.WithValue(stCtx, hookRegistryKey{}, hooks)
+	stCtx, stSpan := config.startSpan(stCtx, "app.startup")
+
+	stRes := make(chan startupResult, 1)
+	config.log(slog.LevelInfo, "startup started", slog.Int("functions", len(startupFns)))
+	r.emit(Event{Type: EventStartupBegan})
+
+	// Prepared up front, since applyPolicy() may replace config while startup functions are still running.
+	stFns, stSlots := config.instrumentSlots("startup", config.recoverPanics(startupFns))
+	stProgress := &startupProgress{step: -1}
+	now, progress := config.now, config.progress
 	go func() {
-
-		for _, fn := range startupFns {
-			if err := fn(stCtx); err != nil {
-				fnErrs <- err
-				stCancel()
+		var warnings []error
+		for i, fn := range stFns {
+			if err := stCtx.Err(); err != nil {
+				stRes <- startupResult{completed: i, err: err, warnings: warnings}
 				return
 			}
+			start := now()
+			stProgress.set(i, start)
+			if err := fn(stCtx); err != nil {
+				if !isBestEffort(err) {
+					stRes <- startupResult{completed: i, err: err, warnings: warnings}
+					stCancel()
+					return
+				}
+				warnings = append(warnings, err)
+			}
+			if progress != nil {
+				progress(i+1, len(stFns), stSlots[i].get(), now().Sub(start))
+			}
 		}
 
-		fnErrs <- nil
+		stRes <- startupResult{completed: len(startupFns), warnings: warnings}
 	}()
 
+	var res *startupResult
 	select {
-	case er.ErrStartup = <-fnErrs:
+	case sr := <-stRes:
+		res = &sr
+		er.ErrStartup = sr.err
+		er.WarningsStartup = sr.warnings
 	case <-stCtx.Done():
+		// A failed startup function cancels stCtx itself right after sending its result, which must not be replaced
+		// with context.Canceled.
+		if stCtx.Err() == context.Canceled && ctx.Err() == nil {
+			sr := <-stRes
+			res = &sr
+			er.ErrStartup = sr.err
+			er.WarningsStartup = sr.warnings
+			break
+		}
+
 		er.ErrStartup = stCtx.Err()
+		if ctx.Err() != nil {
+			er.ErrStartup = context.Cause(ctx)
+		} else if er.ErrStartup == context.DeadlineExceeded {
+			er.ErrStartup = ErrStartupTimeout
+
+			// Identify the function which was still running.
+			if i, start := stProgress.get(); i >= 0 {
+				er.ErrStartup = &StartupError{Step: i, Name: stSlots[i].get(), Err: ErrStartupTimeout, Duration: config.since(start)}
+			}
+		}
+	case <-sigCtx.Done():
+		er.OsSignal = signalFrom(sigCtx)
+		r.awaitProtected(config, er.OsSignal)
 	}
 
 	stCancel()
+	config.endSpan(stSpan, "", er.ErrStartup)
 
-	if er.ErrStartup != nil {
-		return er
+	if er.ErrStartup == nil && er.OsSignal == nil {
+		er.ErrStartup = config.register(ctx)
+		config.reportErr(PhaseStartup, er.ErrStartup)
 	}
 
-	// Monitor the application/OS and document why we're shutting down.
-	osSig := make(chan os.Signal, 1)
-	signal.Notify(osSig, config.signals...)
+	if er.ErrStartup != nil || er.OsSignal != nil {
+		r.setState(StateShuttingDown)
+		defer r.watchForceExit(config, er)()
+		if config.signalReset {
+			sigStop()
+		}
+
+		if er.OsSignal != nil {
+			config = applyPolicy(config, er)
+			forwardSignal(config, er.OsSignal)
+		}
+
+		switch {
+		case er.OsSignal != nil:
+			config.log(slog.LevelWarn, "signal received during startup", slog.String("signal", er.OsSignal.String()))
+			r.emit(Event{Type: EventSignalReceived, Signal: er.OsSignal})
+		case errors.Is(er.ErrStartup, ErrStartupTimeout):
+			config.log(slog.LevelError, "startup timeout exceeded", slog.Duration("timeout", config.startupTimeout),
+				slog.Any("err", er.ErrStartup))
+			config.reportErr(PhaseStartup, er.ErrStartup)
+		default:
+			config.log(slog.LevelError, "startup failed", slog.Any("err", er.ErrStartup))
+		}
+
+		r.cancelContext(er)
+
+		sdCtx, sdCancel := newShutdownContext(ctx, config, er, 0)
+		defer sdCancel()
+
+		// Children spawned by the completed startup functions may depend on what is about to be rolled back.
+		children, childErr := r.stopChildren(sdCtx)
+		er.Children = children
+
+		if len(config.rollbackFns) > 0 || hooks.len() > 0 {
+			// Wait for the in-flight startup function to observe the cancellation so the completed steps are known.
+			if res == nil {
+				sr := <-stRes
+				res = &sr
+				er.WarningsStartup = sr.warnings
+			}
+			rbFns := append(rollbackFor(config.rollbackFns, res.completed), hooks.close()...)
+
+			sdCtx, sdSpan := config.startSpan(sdCtx, "app.rollback")
+
+			rbRun, rbSlots := config.instrumentSlots("rollback", config.withBudget(config.withPerTimeout(config.recoverPanics(rbFns)), false))
+			rbErrs, rbReturned := collect(sdCtx, launchSequentially(sdCtx, rbRun), len(rbRun))
+			er.ErrsShutdown, er.WarningsShutdown = splitBestEffort(attributeTimeout("rollback", rbErrs, rbReturned, rbSlots))
+			config.endSpan(sdSpan, "", errors.Join(er.ErrsShutdown...))
+		}
+
+		if childErr != nil {
+			er.ErrsShutdown = append([]error{childErr}, er.ErrsShutdown...)
+		}
+		if sdCtx.Err() != nil {
+			onShutdownTimeout(config, er)
+		}
+
+		return er, config
+	}
+
+	r.setReady(config.now(), config.since(startedAt))
+	r.setState(StateRunning)
+	config.log(slog.LevelInfo, "startup completed")
+	r.emit(Event{Type: EventReady})
+	notifyUpgradeReady()
+	for _, fn := range config.readyFns {
+		fn()
+	}
+
+	// Launch the run functions and goroutines, which live until shutdown begins.
+	dlCtx, dlCancel := context.WithCancelCause(context.WithoutCancel(ctx))
+	defer dlCancel(context.Canceled)
+	rnCtx, rnCancel := context.WithCancel(context.WithValue(ctx, deadlineKey{}, dlCtx))
+	defer rnCancel()
+
+	r.beginGo(rnCtx)
+	r.beginAttach(rnCtx, config)
+	defer r.watchReload(rnCtx, config)()
+	defer r.watchMaintenance(rnCtx, config)()
+	r.watchdog(rnCtx, config)
+	r.guardResources(rnCtx, config)
+
+	rnErrs := make(chan error, len(config.runFns))
+	for _, fn := range config.recoverPanics(config.runFns) {
+		go func(f Func) {
+			err := f(rnCtx)
+			r.recordRuntime(err)
+			rnErrs <- err
+		}(fn)
+	}
+	rnPending := len(config.runFns)
 
+	// Monitor the application/OS and document why we're shutting down.
 	select {
-	case er.ErrRuntime = <-rte:
-	case er.OsSignal = <-osSig:
+	case req := <-r.rte:
+		er.ErrRuntime, er.Reason = req.err, req.reason
+		er.requested = true
+	case er.ErrRuntime = <-rnErrs:
+		rnPending--
+	case <-sigCtx.Done():
+		er.OsSignal = signalFrom(sigCtx)
+		r.awaitProtected(config, er.OsSignal)
+	case <-ctx.Done():
+		er.ErrContext = context.Cause(ctx)
 	}
 
+	r.setState(StateShuttingDown)
+	defer r.watchForceExit(config, er)()
+	if config.signalReset {
+		sigStop()
+	}
+	defer r.watchKill(config, er)()
+
+	if er.OsSignal != nil {
+		config = applyPolicy(config, er)
+		forwardSignal(config, er.OsSignal)
+	}
+
+	// Deregistration counts against the shutdown timeout, so the shutdown functions get whatever it leaves.
+	deregStart := config.now()
+	deregErrs := config.deregister(ctx)
+	deregTime := config.since(deregStart)
+
+	switch {
+	case er.OsSignal != nil:
+		config.log(slog.LevelInfo, "shutdown started", slog.String("signal", er.OsSignal.String()))
+		r.emit(Event{Type: EventSignalReceived, Signal: er.OsSignal})
+	case er.ErrContext != nil:
+		config.log(slog.LevelInfo, "shutdown started", slog.Any("errContext", er.ErrContext))
+	default:
+		config.log(slog.LevelInfo, "shutdown started", slog.Any("errRuntime", er.ErrRuntime))
+	}
+
+	r.emit(Event{Type: EventShutdownBegan})
+	r.cancelContext(er)
+
+	// Let critical sections guarded by Inhibit() finish before anything is torn down.
+	er.Timing.Inhibited = r.awaitInhibitors(config)
+
+	// Readiness is already off. Give load balancers time to stop routing traffic before anything is torn down.
+	if config.shutdownDelay > 0 {
+		config.log(slog.LevelInfo, "shutdown delay started", slog.Duration("delay", config.shutdownDelay))
+		config.sleep(config.shutdownDelay)
+		er.Timing.ShutdownDelay = config.shutdownDelay
+	}
+
+	r.endGo(rnCancel)
+
 	// Shutdown the application and collect all the errors that occurred during shutdown.
-	sdCtx, sdCancel := context.Background(), nop
+	sdCtx, sdCancel := newShutdownContext(ctx, config, er, deregTime)
+	defer sdCancel()
+	dlStop := context.AfterFunc(sdCtx, func() { dlCancel(ErrShutdownTimeout) })
+	defer dlStop()
+	sdCtx, sdSpan := config.startSpan(sdCtx, "app.shutdown")
+
+	// Stop the children first, since they may depend on what the shutdown functions tear down.
+	children, childErr := r.stopChildren(sdCtx)
+	er.Children = children
+	if childErr != nil {
+		deregErrs = append(deregErrs, childErr)
+	}
+	deregErrs = append(deregErrs, r.endAttach(sdCtx)...)
+
+	sdFns, sdSlots := config.instrumentSlots("shutdown", config.withBudget(config.withPerTimeout(config.recoverPanics(append(slices.Clone(shutdownFns), hooks.close()...))), config.shutdownConcurrency > 0))
+	er.ErrsShutdown, er.Overrun = config.runShutdown(sdCtx, sdFns, sdSlots)
+	er.ErrsShutdown, er.WarningsShutdown = splitBestEffort(er.ErrsShutdown)
+	er.ErrsShutdown = append(deregErrs, er.ErrsShutdown...)
+
+	// Wait for the remaining run functions. Their errors are discarded since shutdown has already begun.
+	for range rnPending {
+		if sdCtx.Err() != nil {
+			break
+		}
+
+		select {
+		case <-rnErrs:
+		case <-sdCtx.Done():
+			er.ErrsShutdown = append(er.ErrsShutdown, ErrShutdownTimeout)
+		}
+	}
+
+	// Wait for the goroutines launched by Go().
+	if sdCtx.Err() == nil {
+		goDone := make(chan struct{})
+		go func() {
+			r.goWg.Wait()
+			close(goDone)
+		}()
+
+		select {
+		case <-goDone:
+		case <-sdCtx.Done():
+			er.ErrsShutdown = append(er.ErrsShutdown, ErrShutdownTimeout)
+		}
+	}
+
+	if sdCtx.Err() != nil {
+		// Cancel synchronously, since the AfterFunc runs on its own goroutine and could lose to the deferred cancel.
+		dlCancel(ErrShutdownTimeout)
+		onShutdownTimeout(config, er)
+	}
+	config.log(slog.LevelInfo, "shutdown completed", slog.Int("errors", len(er.ErrsShutdown)))
+	config.endSpan(sdSpan, "", errors.Join(er.ErrsShutdown...))
+
+	return er, config
+}
+
+// Reports that the shutdown timeout elapsed and captures a goroutine dump if WithGoroutineDumpOnTimeout() was provided.
+func onShutdownTimeout(config *config, er *ExitReason) {
+	config.log(slog.LevelError, "shutdown timeout exceeded", slog.Duration("timeout", config.shutdownTimeout))
+	config.reportErr(PhaseShutdown, ErrShutdownTimeout)
+
+	if !config.dumpOnTimeout {
+		return
+	}
+
+	er.GoroutineDump = goroutineDump()
+	if config.dumpWriter != nil {
+		config.dumpWriter.Write(er.GoroutineDump)
+	}
+}
+
+// The startup function currently running, for reporting which one exceeded the startup timeout.
+type startupProgress struct {
+	mu    sync.Mutex
+	step  int
+	start time.Time
+}
+
+func (sp *startupProgress) set(step int, start time.Time) {
+	sp.mu.Lock()
+	defer sp.mu.Unlock()
+
+	sp.step = step
+	sp.start = start
+}
+
+func (sp *startupProgress) get() (step int, start time.Time) {
+	sp.mu.Lock()
+	defer sp.mu.Unlock()
+
+	return sp.step, sp.start
+}
+
+type startupResult struct {
+	completed int
+	err       error
+	warnings  []error
+}
+
+// Returns the context shutdown functions run under. It retains the values of ctx but is not canceled along with it, and
+// carries the cause of shutdown for CauseFrom(). spent is how much of the shutdown timeout has already been used.
+func newShutdownContext(ctx context.Context, config *config, er *ExitReason, spent time.Duration) (context.Context, context.CancelFunc) {
+	sdCtx := context.WithValue(context.WithoutCancel(ctx), causeKey{}, shutdownCause(er))
+	sdCtx = context.WithValue(sdCtx, exitCauseKey{}, er.Cause())
 	if config.shutdownTimeout > 0 {
-		sdCtx, sdCancel = context.WithTimeout(sdCtx, config.shutdownTimeout)
+		return config.withTimeoutCause(sdCtx, config.shutdownTimeout-spent, ErrShutdownTimeout)
 	}
-	defer sdCancel()
+	return sdCtx, nop
+}
+
+type deadlineKey struct{}
+
+// Returns a context which retains the values of ctx and is only canceled once the shutdown timeout elapses (or the
+// lifecycle ends), given the context passed to a run function, so work in progress when shutdown begins can complete.
+// Otherwise, the returned context is never canceled.
+func untilShutdownDeadline(ctx context.Context) (context.Context, context.CancelFunc) {
+	dlCtx, ok := ctx.Value(deadlineKey{}).(context.Context)
+	if !ok {
+		return context.WithoutCancel(ctx), nop
+	}
+
+	taskCtx, cancel := context.WithCancelCause(context.WithoutCancel(ctx))
+	stop := context.AfterFunc(dlCtx, func() { cancel(context.Cause(dlCtx)) })
+	return taskCtx, func() {
+		stop()
+		cancel(context.Canceled)
+	}
+}
+
+// Returns the rollback functions for the first n completed startup functions, in reverse order.
+func rollbackFor(rollbackFns []Func, n int) []Func {
+	fns := make([]Func, 0, n)
+	for i := min(n, len(rollbackFns)) - 1; i >= 0; i-- {
+		if rollbackFns[i] != nil {
+			fns = append(fns, rollbackFns[i])
+		}
+	}
+	return fns
+}
+
+type fnResult struct {
+	index int
+	err   error
+}
+
+// Runs fns sequentially under ctx in a new goroutine, sending each result to the returned channel.
+func launchSequentially(ctx context.Context, fns []Func) <-chan fnResult {
+	results := make(chan fnResult, len(fns))
+
+	go func() {
+		for i, fn := range fns {
+			results <- fnResult{index: i, err: fn(ctx)}
+		}
+	}()
+
+	return results
+}
+
+// Same as launchSequentially(), but runs up to max of fns at a time. Results are sent in the order they occur.
+func launchConcurrently(ctx context.Context, fns []Func, max int) <-chan fnResult {
+	results := make(chan fnResult, len(fns))
+	sem := make(chan struct{}, max)
 
 	go func() {
-		for _, fn := range shutdownFns {
-			fnErrs <- fn(sdCtx)
+		for i, fn := range fns {
+			sem <- struct{}{}
+			go func(i int, f Func) {
+				defer func() { <-sem }()
+				results <- fnResult{index: i, err: f(ctx)}
+			}(i, fn)
 		}
 	}()
 
-Shutdown:
-	for range shutdownFns {
+	return results
+}
+
+// Reads n results, or fewer if ctx is done first, in which case ErrShutdownTimeout is appended to the errors. Also
+// returns which functions returned.
+func collect(ctx context.Context, results <-chan fnResult, n int) (errs []error, returned []bool) {
+	returned = make([]bool, n)
+
+	for range n {
 		select {
-		case e := <-fnErrs:
-			if e != nil {
-				er.ErrsShutdown = append(er.ErrsShutdown, e)
+		case res := <-results:
+			returned[res.index] = true
+			if res.err != nil {
+				errs = append(errs, res.err)
 			}
-		case <-sdCtx.Done():
-			er.ErrsShutdown = append(er.ErrsShutdown, sdCtx.Err())
-			break Shutdown
+		case <-ctx.Done():
+			errs = append(errs, ErrShutdownTimeout)
+			return errs, returned
 		}
 	}
 
-	return er
+	return errs, returned
+}
+
+// Replaces the ErrShutdownTimeout appended by collect() with a *ShutdownError for each function which had not returned.
+func attributeTimeout(phase string, errs []error, returned []bool, slots []*funcNameSlot) []error {
+	if len(errs) == 0 || errs[len(errs)-1] != ErrShutdownTimeout {
+		return errs
+	}
+
+	errs = errs[:len(errs)-1]
+	for i, ok := range returned {
+		if !ok {
+			errs = append(errs, &ShutdownError{Phase: phase, Index: i, Name: slots[i].get(), Err: ErrShutdownTimeout})
+		}
+	}
+	return errs
+}
+
+// Returns the signals which trigger shutdown unless replaced with WithSignalsReplace().
+func defaultSignals() []os.Signal {
+	return []os.Signal{os.Interrupt, syscall.SIGINT, syscall.SIGTERM}
 }
 
 func nop() {}
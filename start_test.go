@@ -0,0 +1,461 @@
+package graceful
+
+import (
+	"context"
+	"errors"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestStartRunsFunctionsInOrder(t *testing.T) {
+	var order []string
+	step := func(name string) Func {
+		return func(ctx context.Context) error {
+			order = append(order, name)
+			return nil
+		}
+	}
+
+	r := New()
+	r.Shutdown(nil)
+	er := r.Start([]Func{step("start1"), step("start2")}, []Func{step("stop1"), step("stop2")})
+
+	if er.ErrStartup != nil || er.ErrRuntime != nil || len(er.ErrsShutdown) != 0 {
+		t.Fatalf("expected a clean exit, got %+v", er)
+	}
+	want := []string{"start1", "start2", "stop1", "stop2"}
+	if !slices.Equal(order, want) {
+		t.Fatalf("expected %v, got %v", want, order)
+	}
+}
+
+func TestRuntimeErrorTriggersShutdown(t *testing.T) {
+	errBoom := errors.New("boom")
+	r := New()
+
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		r.Shutdown(errBoom)
+	}()
+	er := r.Start(nil, nil)
+
+	if !errors.Is(er.ErrRuntime, errBoom) {
+		t.Fatalf("expected the runtime error, got %v", er.ErrRuntime)
+	}
+}
+
+func TestStartupTimeout(t *testing.T) {
+	hangs := func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+
+	er := New().Start([]Func{hangs}, nil, WithStartupTimeout(20*time.Millisecond))
+	if !errors.Is(er.ErrStartup, ErrStartupTimeout) || !errors.Is(er.ErrStartup, context.DeadlineExceeded) {
+		t.Fatalf("expected the startup timeout, got %v", er.ErrStartup)
+	}
+}
+
+func TestStartupTimeoutIdentifiesFunction(t *testing.T) {
+	ok := func(ctx context.Context) error { return nil }
+
+	er := New().Start([]Func{ok, Named("migrate", hang)}, nil, WithStartupTimeout(20*time.Millisecond))
+
+	var se *StartupError
+	if !errors.As(er.ErrStartup, &se) || se.Step != 1 || se.Name != "migrate" || !errors.Is(se, ErrStartupTimeout) {
+		t.Fatalf("expected the running function to be identified, got %v", er.ErrStartup)
+	}
+	if se.Duration < 20*time.Millisecond {
+		t.Fatalf("expected the function's duration up to the timeout, got %v", se.Duration)
+	}
+	if !strings.Contains(se.Error(), "startup[1] migrate: ") {
+		t.Fatalf("expected the name in the message, got %q", se.Error())
+	}
+}
+
+func TestShutdownErrors(t *testing.T) {
+	errBoom := errors.New("boom")
+	ok := func(ctx context.Context) error { return nil }
+	fail := Named("db", func(ctx context.Context) error { return errBoom })
+
+	r := New()
+	r.Shutdown(nil)
+	er := r.Start(nil, []Func{ok, fail})
+
+	var se *ShutdownError
+	if len(er.ErrsShutdown) != 1 || !errors.As(er.ErrsShutdown[0], &se) || se.Phase != "shutdown" || se.Index != 1 ||
+		se.Name != "db" || !errors.Is(se, errBoom) {
+		t.Fatalf("expected the second shutdown function's error, got %v", er.ErrsShutdown)
+	}
+}
+
+func TestShutdownTimeout(t *testing.T) {
+	hangs := func(ctx context.Context) error {
+		time.Sleep(time.Second)
+		return nil
+	}
+
+	r := New()
+	r.Shutdown(nil)
+	er := r.Start(nil, []Func{hangs}, WithShutdownTimeout(20*time.Millisecond))
+
+	var se *ShutdownError
+	if len(er.ErrsShutdown) != 1 || !errors.As(er.ErrsShutdown[0], &se) || se.Index != 0 ||
+		!errors.Is(se, ErrShutdownTimeout) {
+		t.Fatalf("expected the hanging function to be reported, got %v", er.ErrsShutdown)
+	}
+}
+
+func TestParentContextCanceled(t *testing.T) {
+	type key struct{}
+	errBoom := errors.New("canceled by parent")
+	ctx, cancel := context.WithCancelCause(context.WithValue(context.Background(), key{}, "value"))
+
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		cancel(errBoom)
+	}()
+
+	var sdErr error
+	var sdValue any
+	stop := func(ctx context.Context) error {
+		sdErr, sdValue = ctx.Err(), ctx.Value(key{})
+		return nil
+	}
+
+	er := New().StartContext(ctx, nil, []Func{stop})
+	if !errors.Is(er.ErrContext, errBoom) {
+		t.Fatalf("expected the parent's cause, got %v", er.ErrContext)
+	}
+	if sdErr != nil || sdValue != "value" {
+		t.Fatalf("expected the shutdown context to keep the parent's values without its cancellation, got %v and %v", sdErr, sdValue)
+	}
+}
+
+// Sends sig to the test process, which Start() is expected to be monitoring.
+func signalSelf(sig os.Signal) error {
+	p, err := os.FindProcess(os.Getpid())
+	if err != nil {
+		return err
+	}
+	return p.Signal(sig)
+}
+
+func TestStartupError(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	ran, stopped := false, false
+	fail := func(ctx context.Context) error { return errBoom }
+	next := func(ctx context.Context) error { ran = true; return nil }
+	stop := func(ctx context.Context) error { stopped = true; return nil }
+
+	ok := func(ctx context.Context) error { return nil }
+
+	er := New().Start([]Func{ok, Named("db", fail), next}, []Func{stop})
+	if !errors.Is(er.ErrStartup, errBoom) || ran || stopped {
+		t.Fatalf("expected startup to stop at the failed function, got %+v", er)
+	}
+
+	var se *StartupError
+	if !errors.As(er.ErrStartup, &se) || se.Step != 1 || se.Name != "db" {
+		t.Fatalf("expected the failed function to be identified, got %v", er.ErrStartup)
+	}
+}
+
+func TestStartupErrorIsNotReplaced(t *testing.T) {
+	errBoom := errors.New("boom")
+	fail := func(ctx context.Context) error {
+		return errBoom
+	}
+
+	// The failed function cancels the startup context right after reporting its error, so the two race.
+	for range 200 {
+		er := New().Start([]Func{fail}, nil)
+		if !errors.Is(er.ErrStartup, errBoom) {
+			t.Fatalf("expected the startup error, got %v", er.ErrStartup)
+		}
+	}
+}
+
+func TestSignalDuringStartup(t *testing.T) {
+	hangs := func(ctx context.Context) error {
+		if err := signalSelf(syscall.SIGTERM); err != nil {
+			return err
+		}
+		<-ctx.Done()
+		return ctx.Err()
+	}
+
+	er := New().Start([]Func{hangs}, nil)
+	if er.OsSignal != syscall.SIGTERM || er.ErrStartup != nil {
+		t.Fatalf("expected startup to be aborted by SIGTERM, got %+v", er)
+	}
+}
+
+func TestStartupRollback(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	var rolledBack []int
+	ok := func(ctx context.Context) error { return nil }
+	fail := func(ctx context.Context) error { return errBoom }
+	rollback := func(i int) Func {
+		return func(ctx context.Context) error {
+			rolledBack = append(rolledBack, i)
+			return nil
+		}
+	}
+
+	stopped := false
+	stop := func(ctx context.Context) error {
+		stopped = true
+		return nil
+	}
+
+	er := New().Start([]Func{ok, ok, fail}, []Func{stop}, WithStartupRollback([]Func{rollback(0), nil, rollback(2)}))
+
+	if !errors.Is(er.ErrStartup, errBoom) {
+		t.Fatalf("expected the third startup function to fail, got %v", er.ErrStartup)
+	}
+	if !slices.Equal(rolledBack, []int{0}) {
+		t.Fatalf("expected only the completed steps with a rollback function to be rolled back, got %v", rolledBack)
+	}
+	if stopped {
+		t.Fatal("expected the shutdown functions not to run when startup fails")
+	}
+}
+
+func TestStartupRollbackErrors(t *testing.T) {
+	errBoom := errors.New("boom")
+	ok := func(ctx context.Context) error { return nil }
+	fail := func(ctx context.Context) error { return errBoom }
+
+	er := New().Start([]Func{ok, ok, fail}, nil, WithStartupRollback([]Func{fail, ok}))
+	if len(er.ErrsShutdown) != 1 || !errors.Is(er.ErrsShutdown[0], errBoom) {
+		t.Fatalf("expected the failed rollback to be reported, got %v", er.ErrsShutdown)
+	}
+}
+
+func TestRunFunctionReturnTriggersShutdown(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	canceled := make(chan bool, 1)
+	fails := func(ctx context.Context) error { return errBoom }
+	waits := func(ctx context.Context) error {
+		<-ctx.Done()
+		canceled <- true
+		return nil
+	}
+
+	er := New().Start(nil, nil, WithRun(fails, waits))
+	if !errors.Is(er.ErrRuntime, errBoom) {
+		t.Fatalf("expected the run function's error, got %v", er.ErrRuntime)
+	}
+	select {
+	case <-canceled:
+	default:
+		t.Fatal("expected the other run function to be canceled and awaited")
+	}
+}
+
+func TestRunFunctionsAwaitedAfterShutdown(t *testing.T) {
+	r := New()
+
+	stopped := make(chan struct{})
+	var order []string
+	serve := func(ctx context.Context) error {
+		r.Shutdown(nil)
+		<-stopped
+		order = append(order, "run returned")
+		return nil
+	}
+	stop := func(ctx context.Context) error {
+		order = append(order, "stop")
+		close(stopped)
+		return nil
+	}
+
+	er := r.Start(nil, []Func{stop}, WithRun(serve))
+	if er.ErrRuntime != nil || len(er.ErrsShutdown) != 0 {
+		t.Fatalf("expected a clean exit, got %+v", er)
+	}
+	if !slices.Equal(order, []string{"stop", "run returned"}) {
+		t.Fatalf("expected the run function to be awaited after the shutdown functions, got %v", order)
+	}
+}
+
+func TestExitReasonError(t *testing.T) {
+	errBoom := errors.New("boom")
+	errLate := errors.New("late")
+
+	er := &ExitReason{OsSignal: syscall.SIGTERM, ErrsShutdown: []error{errBoom, errLate}}
+	if got, want := er.Error(), "signal: "+syscall.SIGTERM.String()+"; shutdown: boom; shutdown: late"; got != want {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+	if !errors.Is(er, errLate) {
+		t.Fatal("expected errors.Is() to find a shutdown error")
+	}
+	var se *SignalError
+	if !errors.As(er, &se) || se.Signal != syscall.SIGTERM {
+		t.Fatalf("expected errors.As() to find the signal, got %v", se)
+	}
+	if got := (&ExitReason{}).Error(); got != "exited cleanly" {
+		t.Fatalf("expected a clean exit, got %q", got)
+	}
+}
+
+func TestParentContextDeadlineIsNotStartupTimeout(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	hangs := func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+
+	er := New().StartContext(ctx, []Func{hangs}, nil, WithStartupTimeout(time.Minute))
+	if errors.Is(er.ErrStartup, ErrStartupTimeout) || !errors.Is(er.ErrStartup, context.DeadlineExceeded) {
+		t.Fatalf("expected the parent's deadline rather than the startup timeout, got %v", er.ErrStartup)
+	}
+}
+
+func TestShutdownDelay(t *testing.T) {
+	r := New()
+
+	var ready bool
+	var delayed time.Duration
+	began := time.Now()
+	stop := func(ctx context.Context) error {
+		ready, delayed = r.IsReady(), time.Since(began)
+		return nil
+	}
+
+	r.Shutdown(nil)
+	r.Start(nil, []Func{stop}, WithShutdownDelay(50*time.Millisecond))
+	if ready || delayed < 50*time.Millisecond {
+		t.Fatalf("expected the shutdown functions to run after the delay with readiness off, got %s and ready=%v", delayed, ready)
+	}
+}
+
+func TestConcurrentShutdown(t *testing.T) {
+	r := New()
+	r.Shutdown(nil)
+
+	errBoom := errors.New("boom")
+
+	// Each function waits for the other, so they only return if run concurrently.
+	var wg sync.WaitGroup
+	wg.Add(2)
+	meet := func(ctx context.Context) error {
+		wg.Done()
+		wg.Wait()
+		return errBoom
+	}
+
+	er := r.Start(nil, []Func{meet, meet}, WithConcurrentShutdown(2), WithShutdownTimeout(time.Second))
+
+	if len(er.ErrsShutdown) != 2 || !errors.Is(er.ErrsShutdown[0], errBoom) || !errors.Is(er.ErrsShutdown[1], errBoom) {
+		t.Fatalf("expected both errors, got %v", er.ErrsShutdown)
+	}
+}
+
+func TestConcurrentShutdownLimit(t *testing.T) {
+	r := New()
+	r.Shutdown(nil)
+
+	var running, peak atomic.Int32
+	fn := func(ctx context.Context) error {
+		n := running.Add(1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		running.Add(-1)
+		return nil
+	}
+
+	er := r.Start(nil, []Func{fn, fn, fn, fn, fn}, WithConcurrentShutdown(2))
+
+	if er.ErrsShutdown != nil {
+		t.Fatalf("unexpected shutdown errors: %v", er.ErrsShutdown)
+	}
+	if peak.Load() != 2 {
+		t.Fatalf("expected at most 2 functions at a time, peak was %d", peak.Load())
+	}
+}
+
+func TestConcurrentShutdownInvalid(t *testing.T) {
+	r := New()
+	if er := r.Start(nil, nil, WithConcurrentShutdown(0)); er.ErrStartup == nil {
+		t.Fatal("expected an error for a non-positive concurrency")
+	}
+}
+
+func TestStepTimings(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	r := New()
+	r.Shutdown(nil)
+
+	er := r.Start(
+		[]Func{Named("db", func(ctx context.Context) error { return nil })},
+		[]Func{func(ctx context.Context) error { return errBoom }},
+	)
+
+	steps := er.Timing.Steps
+	if len(steps) != 2 {
+		t.Fatalf("expected 2 steps, got %v", steps)
+	}
+	if st := steps[0]; st.Phase != "startup" || st.Index != 0 || st.Name != "db" || st.Err != nil || st.Start.IsZero() {
+		t.Fatalf("unexpected startup step: %+v", st)
+	}
+	if st := steps[1]; st.Phase != "shutdown" || st.Index != 0 || st.Name != "" || st.Err != errBoom {
+		t.Fatalf("unexpected shutdown step: %+v", st)
+	}
+
+	if stp := er.ToPrintable().Timing.Steps; len(stp) != 2 || stp[1].Err != "boom" || stp[0].Name != "db" {
+		t.Fatalf("unexpected printable steps: %+v", stp)
+	}
+}
+
+func TestAllRuntimeErrors(t *testing.T) {
+	errA, errB, errC := errors.New("a"), errors.New("b"), errors.New("c")
+
+	for _, all := range []bool{false, true} {
+		r := New()
+
+		failFirst := func(ctx context.Context) error { return errA }
+		failLater := func(ctx context.Context) error {
+			<-ctx.Done()
+			r.Shutdown(errB)
+			return errC
+		}
+
+		var opts []Option
+		if all {
+			opts = append(opts, WithAllRuntimeErrors())
+		}
+		er := r.Start(nil, nil, append(opts, WithRun(failFirst, failLater))...)
+
+		if er.ErrRuntime != errA {
+			t.Fatalf("expected the first error in ErrRuntime, got %v", er.ErrRuntime)
+		}
+		if !all {
+			if er.ErrsRuntime != nil {
+				t.Fatalf("expected no ErrsRuntime by default, got %v", er.ErrsRuntime)
+			}
+			continue
+		}
+		if len(er.ErrsRuntime) != 3 || !errors.Is(er, errB) || !errors.Is(er, errC) {
+			t.Fatalf("expected every runtime error, got %v", er.ErrsRuntime)
+		}
+	}
+}
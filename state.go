@@ -0,0 +1,162 @@
+package graceful
+
+import (
+	"net/http"
+	"time"
+)
+
+// The phase of a Runner's lifecycle.
+type State int32
+
+const (
+	StateIdle State = iota
+	StateStarting
+	StateRunning
+	StateShuttingDown
+	StateStopped
+)
+
+func (s State) String() string {
+	switch s {
+	case StateIdle:
+		return "Idle"
+	case StateStarting:
+		return "Starting"
+	case StateRunning:
+		return "Running"
+	case StateShuttingDown:
+		return "ShuttingDown"
+	case StateStopped:
+		return "Stopped"
+	default:
+		return "Unknown"
+	}
+}
+
+// Returns the current phase of the default Runner's lifecycle.
+func CurrentState() State {
+	return defaultRunner.State()
+}
+
+// Reports whether the default Runner has completed startup and shutdown has not begun.
+func IsReady() bool {
+	return defaultRunner.IsReady()
+}
+
+// Reports whether the default Runner is starting, running, or shutting down.
+func IsLive() bool {
+	return defaultRunner.IsLive()
+}
+
+// Returns a snapshot of the default Runner's lifecycle. See Runner.Stats().
+func CurrentStats() Stats {
+	return defaultRunner.Stats()
+}
+
+// Returns an http.Handler for the default Runner which serves /readyz and /livez for health probes.
+func HealthHandler() http.Handler {
+	return defaultRunner.HealthHandler()
+}
+
+// Returns the current phase of this Runner's lifecycle.
+func (r *Runner) State() State {
+	return State(r.state.Load())
+}
+
+// Same as the package-level IsReady(), but scoped to this Runner.
+//
+// Becomes false the moment shutdown begins, before any shutdown function runs.
+func (r *Runner) IsReady() bool {
+	return r.State() == StateRunning
+}
+
+// Same as the package-level IsLive(), but scoped to this Runner.
+func (r *Runner) IsLive() bool {
+	switch r.State() {
+	case StateStarting, StateRunning, StateShuttingDown:
+		return true
+	default:
+		return false
+	}
+}
+
+// Same as the package-level HealthHandler(), but scoped to this Runner.
+//
+// Each endpoint responds with 200 if the check passes and 503 otherwise. The body is the current State.
+func (r *Runner) HealthHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/readyz", r.probe(r.IsReady))
+	mux.HandleFunc("/livez", r.probe(r.IsLive))
+	return mux
+}
+
+func (r *Runner) probe(check func() bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		if !check() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		w.Write([]byte(r.State().String()))
+	}
+}
+
+// A snapshot of a Runner's lifecycle, for introspection (see the expvar subpackage).
+type Stats struct {
+	State State
+
+	// When startup completed, or the zero time if the Runner is not running.
+	ReadyAt time.Time
+
+	// How long the last completed startup took.
+	StartupDuration time.Duration
+
+	// The ExitReason of the previous lifecycle in this process (ie before a restart by Supervise()) or, before the first
+	// one exits, the ExitReason written by the previous process to the file given to WithExitReasonFile(). nil if there
+	// is none.
+	LastExitReason *ExitReason
+}
+
+// Returns a snapshot of this Runner's lifecycle. Safe to call at any time, from any goroutine.
+func (r *Runner) Stats() Stats {
+	r.statsMu.Lock()
+	defer r.statsMu.Unlock()
+
+	return Stats{
+		State:           r.State(),
+		ReadyAt:         r.readyAt,
+		StartupDuration: r.startupDuration,
+		LastExitReason:  r.lastExit,
+	}
+}
+
+// Loads the ExitReason written by the previous process, unless a lifecycle already exited in this one.
+func (r *Runner) loadLastExit(config *config) {
+	if config.exitReasonFile == "" {
+		return
+	}
+
+	r.statsMu.Lock()
+	defer r.statsMu.Unlock()
+
+	if r.lastExit == nil {
+		r.lastExit, _ = LoadLastExitReason(config.exitReasonFile)
+	}
+}
+
+func (r *Runner) setReady(at time.Time, startupDuration time.Duration) {
+	r.statsMu.Lock()
+	defer r.statsMu.Unlock()
+
+	r.readyAt, r.startupDuration = at, startupDuration
+}
+
+func (r *Runner) setExited(er *ExitReason) {
+	r.statsMu.Lock()
+	defer r.statsMu.Unlock()
+
+	r.readyAt, r.lastExit = time.Time{}, er
+}
+
+func (r *Runner) setState(s State) {
+	r.state.Store(int32(s))
+}
@@ -0,0 +1,114 @@
+package graceful
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func probe(t *testing.T, h http.Handler, path string) (int, string) {
+	t.Helper()
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+	return rec.Code, rec.Body.String()
+}
+
+func TestStateAndProbes(t *testing.T) {
+	r := New()
+	h := r.HealthHandler()
+
+	if code, _ := probe(t, h, "/livez"); r.State() != StateIdle || code != http.StatusServiceUnavailable {
+		t.Fatalf("expected an idle Runner not to be live, got %v and %d", r.State(), code)
+	}
+
+	var (
+		starting, running, shuttingDown State
+		readyCode                       int
+		readyBody                       string
+	)
+	start := func(ctx context.Context) error {
+		starting = r.State()
+		return nil
+	}
+	run := func(ctx context.Context) error {
+		running = r.State()
+		readyCode, readyBody = probe(t, h, "/readyz")
+		return nil
+	}
+	stop := func(ctx context.Context) error {
+		shuttingDown = r.State()
+		if r.IsReady() || !r.IsLive() {
+			t.Error("expected a Runner shutting down to be live but not ready")
+		}
+		return nil
+	}
+
+	r.Start([]Func{start}, []Func{stop}, WithRun(run))
+
+	if starting != StateStarting || running != StateRunning || shuttingDown != StateShuttingDown {
+		t.Fatalf("expected Starting, Running, ShuttingDown, got %v, %v, %v", starting, running, shuttingDown)
+	}
+	if readyCode != http.StatusOK || readyBody != "Running" {
+		t.Fatalf("expected /readyz to pass while running, got %d %q", readyCode, readyBody)
+	}
+	if code, body := probe(t, h, "/readyz"); r.State() != StateStopped || code != http.StatusServiceUnavailable || body != "Stopped" {
+		t.Fatalf("expected /readyz to fail once stopped, got %d %q", code, body)
+	}
+}
+
+func TestStats(t *testing.T) {
+	r := New()
+
+	slow := func(ctx context.Context) error {
+		time.Sleep(20 * time.Millisecond)
+		return nil
+	}
+
+	var running Stats
+	run := func(ctx context.Context) error {
+		running = r.Stats()
+		return nil
+	}
+
+	er := r.Start([]Func{slow}, nil, WithRun(run))
+
+	if running.State != StateRunning || running.ReadyAt.IsZero() || running.StartupDuration < 20*time.Millisecond {
+		t.Fatalf("expected a running lifecycle with its startup duration, got %+v", running)
+	}
+	if running.LastExitReason != nil {
+		t.Fatalf("expected no previous exit reason, got %v", running.LastExitReason)
+	}
+
+	stopped := r.Stats()
+	if stopped.State != StateStopped || !stopped.ReadyAt.IsZero() || stopped.LastExitReason != er {
+		t.Fatalf("expected a stopped lifecycle reporting its exit reason, got %+v", stopped)
+	}
+}
+
+func TestStatsLoadsPersistedExitReason(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "exit.json")
+	errBoom := errors.New("boom")
+
+	// The previous process.
+	fail := func(ctx context.Context) error {
+		return errBoom
+	}
+	New().Start(nil, nil, WithRun(fail), WithExitReasonFile(path))
+
+	r := New()
+	var last *ExitReason
+	run := func(ctx context.Context) error {
+		last = r.Stats().LastExitReason
+		return nil
+	}
+	r.Start(nil, nil, WithRun(run), WithExitReasonFile(path))
+
+	if last == nil || last.ErrRuntime == nil || last.ErrRuntime.Error() != errBoom.Error() {
+		t.Fatalf("expected the persisted exit reason, got %v", last)
+	}
+}
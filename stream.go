@@ -0,0 +1,205 @@
+package graceful
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Returned by StreamTracker.Track() once the tracker has begun shutting down.
+var ErrStreamsClosed = errors.New("stream tracker is shutting down")
+
+// A long-lived connection tracked by a StreamTracker, such as a websocket or a server-sent events stream.
+type Stream interface {
+	// Asks the client to end the stream, ie by sending a websocket close frame or a final server-sent event. It may be
+	// called concurrently with the handler's own writes, so it must be safe to do so.
+	SendClose(ctx context.Context) error
+
+	// Closes the underlying connection.
+	Close() error
+}
+
+type stream struct {
+	sendClose Func
+	close     func() error
+}
+
+func (s *stream) SendClose(ctx context.Context) error {
+	if s.sendClose == nil {
+		return nil
+	}
+	return s.sendClose(ctx)
+}
+
+func (s *stream) Close() error {
+	return s.close()
+}
+
+// Creates a Stream from a pair of functions. sendClose may be nil, in which case the stream is closed once the ack
+// timeout elapses.
+func NewStream(sendClose Func, close func() error) Stream {
+	return &stream{
+		sendClose: sendClose,
+		close:     close,
+	}
+}
+
+// Tracks long-lived connections so they can be ended gracefully, which a plain drain cannot do since they never finish
+// on their own. For example, with github.com/gorilla/websocket:
+//
+//	streams := graceful.NewStreamTracker(2 * time.Second)
+//	graceful.Start(nil, []graceful.Func{streams.Shutdown, stop}, graceful.WithRun(run))
+//
+//	// In the handler, after upgrading:
+//	done, err := streams.Track(graceful.NewStream(func(ctx context.Context) error {
+//		deadline, _ := ctx.Deadline()
+//		msg := websocket.FormatCloseMessage(websocket.CloseGoingAway, "server shutting down")
+//		return conn.WriteControl(websocket.CloseMessage, msg, deadline)
+//	}, conn.Close))
+//	if err != nil {
+//		conn.Close()
+//		return
+//	}
+//	defer done()
+//	// Read until the client acknowledges the close frame (or the connection is closed).
+//
+// Shut the tracker down before the server, since closing the server does not end hijacked connections.
+type StreamTracker struct {
+	ackTimeout time.Duration
+
+	mu      sync.Mutex
+	streams map[*trackedStream]struct{}
+	idle    chan struct{}
+	closed  bool
+}
+
+type trackedStream struct {
+	Stream
+}
+
+// Creates a StreamTracker which gives clients up to ackTimeout to end their streams after being asked to at shutdown.
+// If ackTimeout is not positive, they are given until the shutdown context is done.
+func NewStreamTracker(ackTimeout time.Duration) *StreamTracker {
+	idle := make(chan struct{})
+	close(idle)
+
+	return &StreamTracker{
+		ackTimeout: ackTimeout,
+		streams:    map[*trackedStream]struct{}{},
+		idle:       idle,
+	}
+}
+
+// Tracks s until done is called, which the handler should do once the stream has ended (ie the read loop returned
+// because the client acknowledged the close). done is safe to call more than once. Returns ErrStreamsClosed once
+// Shutdown() has been called, in which case the caller should close the connection itself.
+func (st *StreamTracker) Track(s Stream) (done func(), err error) {
+	ts := &trackedStream{Stream: s}
+
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	if st.closed {
+		return nop, ErrStreamsClosed
+	}
+
+	if len(st.streams) == 0 {
+		st.idle = make(chan struct{})
+	}
+	st.streams[ts] = struct{}{}
+
+	return func() { st.untrack(ts) }, nil
+}
+
+// Returns the number of tracked streams.
+func (st *StreamTracker) Len() int {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	return len(st.streams)
+}
+
+// A shutdown function which stops accepting streams, asks every tracked stream to close, and waits up to the ack
+// timeout for their handlers to call done. The streams which remain are then closed forcibly.
+//
+// Returns the errors of SendClose(). If ctx is done before the streams end, the remaining ones are closed and the
+// context's error is also returned along with their number.
+func (st *StreamTracker) Shutdown(ctx context.Context) error {
+	st.mu.Lock()
+	st.closed = true
+	streams := st.snapshot()
+	idle := st.idle
+	st.mu.Unlock()
+
+	ackCtx, ackCancel := context.WithCancel(ctx)
+	if st.ackTimeout > 0 {
+		ackCancel()
+		ackCtx, ackCancel = context.WithTimeout(ctx, st.ackTimeout)
+	}
+	defer ackCancel()
+
+	// Ask concurrently, so a client which is slow to read does not delay the others.
+	var wg sync.WaitGroup
+	errCh := make(chan error, len(streams))
+	for _, ts := range streams {
+		wg.Add(1)
+		go func(ts *trackedStream) {
+			defer wg.Done()
+			if err := ts.SendClose(ackCtx); err != nil {
+				errCh <- fmt.Errorf("failed to send close: %w", err)
+			}
+		}(ts)
+	}
+	wg.Wait()
+	close(errCh)
+
+	var errs []error
+	for err := range errCh {
+		errs = append(errs, err)
+	}
+
+	select {
+	case <-idle:
+		return errors.Join(errs...)
+	case <-ackCtx.Done():
+	}
+
+	st.mu.Lock()
+	remaining := st.snapshot()
+	st.mu.Unlock()
+
+	for _, ts := range remaining {
+		ts.Close()
+		st.untrack(ts)
+	}
+
+	if ctx.Err() != nil {
+		errs = append(errs, fmt.Errorf("%d stream(s) still open: %w", len(remaining), ctx.Err()))
+	}
+	return errors.Join(errs...)
+}
+
+// Must be called with mu held.
+func (st *StreamTracker) snapshot() []*trackedStream {
+	streams := make([]*trackedStream, 0, len(st.streams))
+	for ts := range st.streams {
+		streams = append(streams, ts)
+	}
+	return streams
+}
+
+func (st *StreamTracker) untrack(ts *trackedStream) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	if _, ok := st.streams[ts]; !ok {
+		return
+	}
+
+	delete(st.streams, ts)
+	if len(st.streams) == 0 {
+		close(st.idle)
+	}
+}
@@ -0,0 +1,78 @@
+package graceful
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestStreamTracker(t *testing.T) {
+	st := NewStreamTracker(20 * time.Millisecond)
+
+	// Acknowledges the close, so its handler ends the stream.
+	var done func()
+	var closedAcked atomic.Bool
+	done, err := st.Track(NewStream(func(ctx context.Context) error {
+		go done()
+		return nil
+	}, func() error {
+		closedAcked.Store(true)
+		return nil
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Ignores the close, so it is closed once the ack timeout elapses.
+	var closedIgnored atomic.Bool
+	if _, err := st.Track(NewStream(nil, func() error {
+		closedIgnored.Store(true)
+		return nil
+	})); err != nil {
+		t.Fatal(err)
+	}
+
+	if st.Len() != 2 {
+		t.Fatalf("expected 2 tracked streams, got %d", st.Len())
+	}
+	if err := st.Shutdown(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if closedAcked.Load() || !closedIgnored.Load() {
+		t.Fatalf("expected only the stream which ignored the close to be closed, got %v and %v",
+			closedAcked.Load(), closedIgnored.Load())
+	}
+	if st.Len() != 0 {
+		t.Fatalf("expected no tracked streams, got %d", st.Len())
+	}
+
+	if _, err := st.Track(NewStream(nil, func() error { return nil })); !errors.Is(err, ErrStreamsClosed) {
+		t.Fatalf("expected ErrStreamsClosed after shutdown, got %v", err)
+	}
+}
+
+func TestStreamTrackerContextDone(t *testing.T) {
+	errSend := errors.New("send")
+	st := NewStreamTracker(0)
+
+	var closed atomic.Bool
+	if _, err := st.Track(NewStream(func(ctx context.Context) error { return errSend }, func() error {
+		closed.Store(true)
+		return nil
+	})); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	err := st.Shutdown(ctx)
+	if !errors.Is(err, errSend) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected the send error and the context's error, got %v", err)
+	}
+	if !closed.Load() {
+		t.Fatal("expected the remaining stream to be closed")
+	}
+}
@@ -0,0 +1,156 @@
+package graceful
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"time"
+)
+
+// Selects which exits Supervise() restarts the lifecycle after.
+type RestartPolicy int
+
+const (
+	// Never restart. Supervise() behaves like Start().
+	RestartNever RestartPolicy = iota
+
+	// Restart if shutdown was triggered by a runtime error.
+	RestartOnFailure
+
+	// Restart if shutdown was triggered at runtime, with or without an error (ie a run function returned), unless it was
+	// requested without an error with Shutdown(nil) or Stop().
+	RestartAlways
+)
+
+// Configures Supervise() to restart up to maxRetries times according to policy. The first restart waits backoff and
+// each subsequent one waits twice as long as the previous. Default: RestartNever.
+func WithRestartPolicy(policy RestartPolicy, maxRetries int, backoff time.Duration) Option {
+	return func(config *config) error {
+		if maxRetries < 0 {
+			return invalidOption("max retries must not be negative")
+		}
+		if backoff < 0 {
+			return invalidOption("restart backoff must not be negative")
+		}
+		config.restartPolicy = policy
+		config.maxRetries = maxRetries
+		config.restartBackoff = backoff
+		return nil
+	}
+}
+
+// Same as Start(), but re-runs the whole startup, run, and shutdown cycle according to WithRestartPolicy().
+//
+// The same functions are run again on every restart, so they must be safe to reuse. Adapters which only work once,
+// such as HTTPServer(), must be created per lifecycle with SuperviseFactory() instead.
+//
+// Exits caused by OS signals, parent context cancellation, startup errors, or Shutdown(nil) and Stop() are never
+// restarted. A signal, Shutdown(), or Stop() received while waiting to restart ends supervision. Returns the final
+// ExitReason, with ExitReason.Restarts set.
+func Supervise(startupFns []Func, shutdownFns []Func, opts ...Option) *ExitReason {
+	return defaultRunner.Supervise(startupFns, shutdownFns, opts...)
+}
+
+// Same as the package-level Supervise(), but scoped to this Runner.
+func (r *Runner) Supervise(startupFns []Func, shutdownFns []Func, opts ...Option) *ExitReason {
+	return r.SuperviseFactory(context.Background(), func() ([]Func, []Func, []Option) {
+		return startupFns, shutdownFns, nil
+	}, opts...)
+}
+
+// Same as Supervise(), but newLifecycle is called before every start to create the functions and options of that
+// lifecycle, so adapters which only work once (ie HTTPServer(), since an http.Server cannot be reused after shutdown)
+// are created fresh on restart:
+//
+//	graceful.SuperviseFactory(ctx, func() ([]graceful.Func, []graceful.Func, []graceful.Option) {
+//		run, stop := graceful.HTTPServer(&http.Server{Addr: ":8080", Handler: mux}, nil)
+//		return nil, []graceful.Func{stop}, []graceful.Option{graceful.WithRun(run)}
+//	}, graceful.WithRestartPolicy(graceful.RestartOnFailure, 5, time.Second))
+//
+// The options returned by newLifecycle are applied after opts. Each lifecycle is started with StartContext(ctx), and
+// nothing is restarted once ctx is done.
+func SuperviseFactory(ctx context.Context, newLifecycle func() (startupFns []Func, shutdownFns []Func, opts []Option), opts ...Option) *ExitReason {
+	return defaultRunner.SuperviseFactory(ctx, newLifecycle, opts...)
+}
+
+// Same as the package-level SuperviseFactory(), but scoped to this Runner.
+func (r *Runner) SuperviseFactory(ctx context.Context, newLifecycle func() (startupFns []Func, shutdownFns []Func, opts []Option), opts ...Option) *ExitReason {
+	config := &config{runner: r, signals: defaultSignals()}
+	if err := parseOptions(config, opts); err != nil {
+		return &ExitReason{ErrStartup: err}
+	}
+
+	backoff := config.restartBackoff
+	for restarts := 0; ; restarts++ {
+		startupFns, shutdownFns, lcOpts := newLifecycle()
+		er := r.StartContext(ctx, startupFns, shutdownFns, append(opts[:len(opts):len(opts)], lcOpts...)...)
+		er.Restarts = restarts
+
+		if restarts >= config.maxRetries || ctx.Err() != nil || !shouldRestart(config.restartPolicy, er) {
+			return er
+		}
+
+		config.log(slog.LevelWarn, "restarting", slog.Int("restart", restarts+1), slog.Duration("backoff", backoff),
+			slog.Any("errRuntime", er.ErrRuntime))
+
+		// Discard any shutdown requested after the previous lifecycle's shutdown began, so only a request made while
+		// waiting ends supervision.
+		r.drainRuntime()
+
+		switch sig, req, err := r.waitBackoff(ctx, config, backoff); {
+		case sig != nil:
+			er.OsSignal = sig
+			return er
+		case req != nil:
+			er.Reason = req.reason
+			return er
+		case err != nil:
+			er.ErrContext = err
+			return er
+		}
+		backoff *= 2
+	}
+}
+
+func shouldRestart(policy RestartPolicy, er *ExitReason) bool {
+	if er.ErrStartup != nil || er.OsSignal != nil || er.ErrContext != nil {
+		return false
+	}
+
+	switch policy {
+	case RestartOnFailure:
+		return er.ErrRuntime != nil
+	case RestartAlways:
+		return er.ErrRuntime != nil || !er.requested
+	default:
+		return false
+	}
+}
+
+// Waits d, returning early with the signal if a shutdown signal is received, the request if Shutdown() (or a variant)
+// is called, or the cause of ctx if it is done.
+func (r *Runner) waitBackoff(ctx context.Context, config *config, d time.Duration) (os.Signal, *shutdownRequest, error) {
+	sigCtx, sigStop := r.notifyContext(context.Background(), config.signals...)
+	defer sigStop()
+
+	tCtx, cancel := config.withTimeout(context.Background(), d)
+	defer cancel()
+
+	select {
+	case <-sigCtx.Done():
+		return signalFrom(sigCtx), nil, nil
+	case req := <-r.rte:
+		return nil, &req, nil
+	case <-ctx.Done():
+		return nil, nil, context.Cause(ctx)
+	case <-tCtx.Done():
+		return nil, nil, nil
+	}
+}
+
+func (r *Runner) drainRuntime() {
+	select {
+	case <-r.rte:
+	default:
+	}
+}
@@ -0,0 +1,129 @@
+package graceful
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"testing"
+	"time"
+)
+
+func TestSuperviseRestartsOnFailure(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	runs := 0
+	run := func(ctx context.Context) error {
+		runs++
+		return errBoom
+	}
+
+	er := New().Supervise(nil, nil, WithRun(run), WithRestartPolicy(RestartOnFailure, 2, time.Millisecond))
+	if runs != 3 || er.Restarts != 2 {
+		t.Fatalf("expected 3 runs and 2 restarts, got %d runs and %d restarts", runs, er.Restarts)
+	}
+	if !errors.Is(er.ErrRuntime, errBoom) {
+		t.Fatalf("expected the last runtime error, got %v", er.ErrRuntime)
+	}
+}
+
+func TestSuperviseCommand(t *testing.T) {
+	run, stop := Command(exec.Command(lookPath(t, "true")))
+
+	er := New().Supervise(nil, []Func{stop}, WithRun(run), WithRestartPolicy(RestartOnFailure, 2, time.Millisecond))
+	if er.Restarts != 2 {
+		t.Fatalf("expected 2 restarts, got %d", er.Restarts)
+	}
+	if er.ErrRuntime == nil || errors.Is(er.ErrRuntime, exec.ErrNotFound) {
+		t.Fatalf("expected the command to exit unexpectedly, got %v", er.ErrRuntime)
+	}
+}
+
+func TestSuperviseAlwaysSkipsRequestedShutdown(t *testing.T) {
+	r := New()
+
+	runs := 0
+	run := func(ctx context.Context) error {
+		runs++
+		r.Shutdown(nil)
+		<-ctx.Done()
+		return nil
+	}
+
+	er := r.Supervise(nil, nil, WithRun(run), WithRestartPolicy(RestartAlways, 3, time.Millisecond))
+	if runs != 1 || er.Restarts != 0 {
+		t.Fatalf("expected Shutdown(nil) not to be restarted, got %d runs", runs)
+	}
+}
+
+func TestSuperviseAlwaysRestartsReturnedRun(t *testing.T) {
+	runs := 0
+	run := func(ctx context.Context) error {
+		runs++
+		return nil
+	}
+
+	er := New().Supervise(nil, nil, WithRun(run), WithRestartPolicy(RestartAlways, 2, time.Millisecond))
+	if runs != 3 || er.Restarts != 2 {
+		t.Fatalf("expected 3 runs, got %d", runs)
+	}
+}
+
+func TestSuperviseFactoryCreatesFreshLifecycles(t *testing.T) {
+	var lifecycles []int
+	newLifecycle := func() ([]Func, []Func, []Option) {
+		i := len(lifecycles)
+		lifecycles = append(lifecycles, i)
+
+		fail := func(ctx context.Context) error {
+			return fmt.Errorf("lifecycle %d failed", i)
+		}
+		return nil, nil, []Option{WithRun(fail)}
+	}
+
+	er := New().SuperviseFactory(context.Background(), newLifecycle, WithRestartPolicy(RestartOnFailure, 2, time.Millisecond))
+	if len(lifecycles) != 3 || er.Restarts != 2 {
+		t.Fatalf("expected 3 lifecycles, got %d", len(lifecycles))
+	}
+	if er.ErrRuntime == nil || er.ErrRuntime.Error() != "lifecycle 2 failed" {
+		t.Fatalf("expected the last lifecycle's error, got %v", er.ErrRuntime)
+	}
+}
+
+func TestSuperviseStopsOnceContextDone(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	runs := 0
+	newLifecycle := func() ([]Func, []Func, []Option) {
+		return nil, nil, []Option{WithRun(func(context.Context) error {
+			runs++
+			cancel()
+			return errors.New("failed")
+		})}
+	}
+
+	New().SuperviseFactory(ctx, newLifecycle, WithRestartPolicy(RestartOnFailure, 3, time.Millisecond))
+	if runs != 1 {
+		t.Fatalf("expected no restart once ctx is done, got %d runs", runs)
+	}
+}
+
+func TestSuperviseShutdownDuringBackoff(t *testing.T) {
+	r := New()
+
+	runs := 0
+	run := func(ctx context.Context) error {
+		runs++
+		go func() {
+			time.Sleep(20 * time.Millisecond)
+			r.Stop()
+		}()
+		return errors.New("failed")
+	}
+
+	er := r.Supervise(nil, nil, WithRun(run), WithRestartPolicy(RestartOnFailure, 3, time.Minute))
+	if runs != 1 || er.Restarts != 0 || er.Reason != "stopped" {
+		t.Fatalf("expected Stop() to end supervision during the backoff, got %d runs (%q)", runs, er.Reason)
+	}
+}
@@ -0,0 +1,117 @@
+// Package systemd integrates a graceful.Runner with systemd's service notification protocol (sd_notify), so
+// applications can run under Type=notify units with watchdog support.
+package systemd
+
+import (
+	"context"
+	"errors"
+	"net"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/bryhen/graceful"
+)
+
+// Returned by Notify() when the process was not started by systemd with a notification socket.
+var ErrNoSocket = errors.New("NOTIFY_SOCKET is not set")
+
+// Sends state (ie "READY=1") to the socket in NOTIFY_SOCKET.
+func Notify(state string) error {
+	addr := os.Getenv("NOTIFY_SOCKET")
+	if addr == "" {
+		return ErrNoSocket
+	}
+
+	// A leading @ denotes a socket in the abstract namespace.
+	if addr[0] == '@' {
+		addr = "\x00" + addr[1:]
+	}
+
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: addr, Net: "unixgram"})
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	_, err = conn.Write([]byte(state))
+	return err
+}
+
+// Returns the interval at which systemd expects watchdog pings, or 0 if the watchdog is not enabled for this process.
+func WatchdogInterval() time.Duration {
+	if pid := os.Getenv("WATCHDOG_PID"); pid != "" && pid != strconv.Itoa(os.Getpid()) {
+		return 0
+	}
+
+	usec, err := strconv.ParseInt(os.Getenv("WATCHDOG_USEC"), 10, 64)
+	if err != nil || usec <= 0 {
+		return 0
+	}
+
+	return time.Duration(usec) * time.Microsecond
+}
+
+// Notifies systemd of the lifecycle: READY=1 once startup completes, STOPPING=1 as the very first act of shutdown, and
+// WATCHDOG=1 at half the watchdog interval during the run phase if WATCHDOG_USEC is set. READY=1 is sent by
+// graceful.WithOnReady() and STOPPING=1 by graceful.WithRegistration(), so they are sent by the lifecycle itself and
+// cannot be missed.
+//
+// Does nothing if NOTIFY_SOCKET is not set.
+func WithNotify() graceful.Option {
+	if os.Getenv("NOTIFY_SOCKET") == "" {
+		return nil
+	}
+
+	var mu sync.Mutex
+	var stopWatchdog func()
+
+	ready := func() {
+		if Notify("READY=1") != nil {
+			return
+		}
+
+		interval := WatchdogInterval()
+		if interval <= 0 {
+			return
+		}
+
+		ticker := time.NewTicker(interval / 2)
+		done, stopped := make(chan struct{}), make(chan struct{})
+		go func() {
+			defer close(stopped)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ticker.C:
+					Notify("WATCHDOG=1")
+				case <-done:
+					return
+				}
+			}
+		}()
+
+		mu.Lock()
+		stopWatchdog = func() {
+			close(done)
+			<-stopped
+		}
+		mu.Unlock()
+	}
+
+	// Waits for the watchdog to stop, so no ping follows STOPPING=1.
+	stopping := func(ctx context.Context) error {
+		mu.Lock()
+		if stopWatchdog != nil {
+			stopWatchdog()
+			stopWatchdog = nil
+		}
+		mu.Unlock()
+
+		Notify("STOPPING=1")
+		return nil
+	}
+
+	return graceful.WithOptions(graceful.WithOnReady(ready), graceful.WithRegistration(nil, stopping))
+}
@@ -0,0 +1,87 @@
+package systemd
+
+import (
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/bryhen/graceful"
+)
+
+// Listens on a notification socket and points NOTIFY_SOCKET at it.
+func listenNotify(t *testing.T) *net.UnixConn {
+	t.Helper()
+
+	// Socket paths are limited to ~100 bytes, which t.TempDir() may exceed.
+	dir, err := os.MkdirTemp("", "sd")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	path := filepath.Join(dir, "notify.sock")
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: path, Net: "unixgram"})
+	if err != nil {
+		t.Skipf("unixgram sockets not supported: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	t.Setenv("NOTIFY_SOCKET", path)
+	return conn
+}
+
+// Returns the states received until none arrives for a while.
+func receive(t *testing.T, conn *net.UnixConn) []string {
+	t.Helper()
+
+	var states []string
+	buf := make([]byte, 256)
+	for {
+		conn.SetReadDeadline(time.Now().Add(100 * time.Millisecond))
+		n, err := conn.Read(buf)
+		if err != nil {
+			return states
+		}
+		states = append(states, string(buf[:n]))
+	}
+}
+
+func TestWithNotify(t *testing.T) {
+	conn := listenNotify(t)
+	t.Setenv("WATCHDOG_USEC", "20000")
+
+	run := func(ctx context.Context) error {
+		time.Sleep(50 * time.Millisecond)
+		return nil
+	}
+
+	graceful.New().Start(nil, nil, graceful.WithRun(run), WithNotify())
+
+	states := receive(t, conn)
+	if len(states) < 3 || states[0] != "READY=1" || states[len(states)-1] != "STOPPING=1" {
+		t.Fatalf("expected READY=1, watchdog pings, then STOPPING=1, got %q", states)
+	}
+	for _, state := range states[1 : len(states)-1] {
+		if state != "WATCHDOG=1" {
+			t.Fatalf("expected only watchdog pings between READY=1 and STOPPING=1, got %q", states)
+		}
+	}
+}
+
+func TestWithNotifyStartupFailure(t *testing.T) {
+	conn := listenNotify(t)
+
+	fail := func(ctx context.Context) error {
+		return context.DeadlineExceeded
+	}
+
+	graceful.New().Start([]graceful.Func{fail}, nil, WithNotify())
+
+	if states := receive(t, conn); slices.Contains(states, "READY=1") {
+		t.Fatalf("expected no READY=1 when startup fails, got %q", states)
+	}
+}
@@ -0,0 +1,106 @@
+package graceful
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// Each shutdown function's context has a deadline of its share of the shutdown timeout remaining when it starts, in
+// proportion to its weight, so an early function which uses all the time it is given cannot starve the ones after it.
+// The weights apply to the shutdown functions in order; functions without a weight (including those registered with
+// OnShutdown()) have a weight of 1, so no weights splits the remaining time equally. Rollback functions are budgeted the
+// same way.
+//
+// A function which exceeds its share is not abandoned, its context is just done; combine with WithPerShutdownTimeout()
+// to abandon functions. Requires WithShutdownTimeout(). Shutdown functions run with WithConcurrentShutdown() are not
+// budgeted, since they do not wait on each other. Default: every function may use all of the remaining time.
+func WithShutdownBudget(weights ...float64) Option {
+	return func(config *config) error {
+		for _, w := range weights {
+			if w <= 0 {
+				return invalidOption("shutdown budget weights must be positive")
+			}
+		}
+		config.budget = true
+		config.budgetWeights = weights
+		return nil
+	}
+}
+
+// Wraps each of fns so its context's deadline is its share of the time remaining. See WithShutdownBudget(). Returns fns
+// unchanged if no budget was configured or fns run concurrently.
+func (c *config) withBudget(fns []Func, concurrent bool) []Func {
+	if !c.budget || c.shutdownTimeout <= 0 || concurrent {
+		return fns
+	}
+
+	weight := func(i int) float64 {
+		if i < len(c.budgetWeights) {
+			return c.budgetWeights[i]
+		}
+		return 1
+	}
+
+	wrapped := make([]Func, len(fns))
+	for i, fn := range fns {
+		wrapped[i] = func(ctx context.Context) error {
+			deadline, ok := ctx.Deadline()
+			if !ok {
+				return fn(ctx)
+			}
+
+			var remainingWeight float64
+			for j := i; j < len(fns); j++ {
+				remainingWeight += weight(j)
+			}
+
+			share := time.Duration(float64(deadline.Sub(c.now())) * weight(i) / remainingWeight)
+			ctx, cancel := c.withTimeout(ctx, share)
+			defer cancel()
+
+			return fn(ctx)
+		}
+	}
+
+	return wrapped
+}
+
+// Wraps each of fns so it is given at most the per shutdown timeout. If a function ignores its context and is still
+// running once it elapses, it is abandoned and ErrShutdownFuncTimeout is returned, or ErrShutdownTimeout if the shutdown
+// timeout elapsed first. Returns fns unchanged if no per shutdown timeout was configured.
+func (c *config) withPerTimeout(fns []Func) []Func {
+	d := c.perShutdownTimeout
+	if d <= 0 {
+		return fns
+	}
+
+	wrapped := make([]Func, len(fns))
+	for i, fn := range fns {
+		wrapped[i] = func(ctx context.Context) error {
+			ctx, cancel := c.withTimeoutCause(ctx, d, ErrShutdownFuncTimeout)
+			defer cancel()
+
+			errCh := make(chan error, 1)
+			go func() {
+				errCh <- fn(ctx)
+			}()
+
+			select {
+			case err := <-errCh:
+				return err
+			case <-ctx.Done():
+				// The shutdown timeout may elapse first, in which case it is the one reported.
+				if cause := context.Cause(ctx); errors.Is(cause, ErrShutdownTimeout) {
+					return ErrShutdownTimeout
+				}
+				if ctx.Err() == context.DeadlineExceeded {
+					return ErrShutdownFuncTimeout
+				}
+				return ctx.Err()
+			}
+		}
+	}
+
+	return wrapped
+}
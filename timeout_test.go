@@ -0,0 +1,187 @@
+package graceful
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// Ignores its context, so it must be abandoned.
+func hang(ctx context.Context) error {
+	time.Sleep(time.Second)
+	return nil
+}
+
+func TestPerShutdownTimeout(t *testing.T) {
+	r := New()
+	r.Shutdown(nil)
+
+	ran := false
+	after := func(ctx context.Context) error {
+		ran = true
+		return nil
+	}
+
+	er := r.Start(nil, []Func{hang, after}, WithPerShutdownTimeout(20*time.Millisecond), WithShutdownTimeout(500*time.Millisecond))
+
+	if len(er.ErrsShutdown) != 1 || !errors.Is(er.ErrsShutdown[0], ErrShutdownFuncTimeout) {
+		t.Fatalf("expected only ErrShutdownFuncTimeout, got %v", er.ErrsShutdown)
+	}
+	if !ran {
+		t.Fatal("expected the following shutdown function to run")
+	}
+}
+
+func TestPerShutdownTimeoutReportsShutdownTimeout(t *testing.T) {
+	// The shutdown timeout elapses before the function's own timeout.
+	ctx, cancel := context.WithTimeoutCause(context.Background(), 20*time.Millisecond, ErrShutdownTimeout)
+	defer cancel()
+
+	if err := (&config{perShutdownTimeout: time.Second}).withPerTimeout([]Func{hang})[0](ctx); err != ErrShutdownTimeout {
+		t.Fatalf("expected ErrShutdownTimeout, got %v", err)
+	}
+}
+
+func TestPerShutdownTimeoutDisabled(t *testing.T) {
+	fns := []Func{hang}
+	if wrapped := (&config{}).withPerTimeout(fns); &wrapped[0] != &fns[0] {
+		t.Fatal("expected fns to be returned unchanged")
+	}
+}
+
+func TestShutdownTimeoutBeforePerShutdownTimeout(t *testing.T) {
+	r := New()
+	r.Shutdown(nil)
+
+	// The second function's own timeout would elapse after the shutdown timeout.
+	er := r.Start(nil, []Func{hang, hang}, WithPerShutdownTimeout(40*time.Millisecond), WithShutdownTimeout(60*time.Millisecond))
+
+	if !errors.Is(errors.Join(er.ErrsShutdown...), ErrShutdownTimeout) {
+		t.Fatalf("expected ErrShutdownTimeout, got %v", er.ErrsShutdown)
+	}
+}
+
+func TestGoroutineDumpOnTimeout(t *testing.T) {
+	r := New()
+	r.Shutdown(nil)
+
+	var w bytes.Buffer
+	er := r.Start(nil, []Func{hang}, WithShutdownTimeout(20*time.Millisecond), WithGoroutineDumpOnTimeout(&w))
+
+	if !bytes.Contains(er.GoroutineDump, []byte("goroutine")) || !bytes.Equal(w.Bytes(), er.GoroutineDump) {
+		t.Fatalf("expected the dump to be captured and written, got %q", er.GoroutineDump)
+	}
+	if er.ToPrintable().GoroutineDump != string(er.GoroutineDump) {
+		t.Fatal("expected the dump in the printable form")
+	}
+}
+
+func TestNoGoroutineDumpWithoutTimeout(t *testing.T) {
+	r := New()
+	r.Shutdown(nil)
+
+	er := r.Start(nil, nil, WithShutdownTimeout(time.Second), WithGoroutineDumpOnTimeout(nil))
+	if er.GoroutineDump != nil {
+		t.Fatal("expected no dump when the shutdown timeout did not elapse")
+	}
+}
+
+func TestShutdownBudget(t *testing.T) {
+	r := New()
+	r.Shutdown(nil)
+
+	remaining := make([]time.Duration, 3)
+	record := func(i int) Func {
+		return func(ctx context.Context) error {
+			deadline, ok := ctx.Deadline()
+			if ok {
+				remaining[i] = time.Until(deadline)
+			}
+			return nil
+		}
+	}
+
+	er := r.Start(nil, []Func{record(0), record(1), record(2)}, WithShutdownTimeout(4*time.Second), WithShutdownBudget(1, 3))
+	if er.HasErrors() {
+		t.Fatalf("unexpected errors: %v", er)
+	}
+
+	// The weights are 1, 3, and 1 (the default) of a total of 5, and each share is of the time remaining when it starts.
+	within := func(d, want time.Duration) bool { return d <= want && d > want-time.Second/2 }
+	if !within(remaining[0], 4*time.Second/5) {
+		t.Fatalf("expected the first function to be given a fifth of the timeout, got %v", remaining[0])
+	}
+	if !within(remaining[1], 3*time.Second) {
+		t.Fatalf("expected the second function to be given three quarters of the rest, got %v", remaining[1])
+	}
+	if !within(remaining[2], 4*time.Second) {
+		t.Fatalf("expected the last function to be given all of the rest, got %v", remaining[2])
+	}
+}
+
+func TestShutdownBudgetConcurrent(t *testing.T) {
+	r := New()
+	r.Shutdown(nil)
+
+	var remaining time.Duration
+	record := func(ctx context.Context) error {
+		deadline, _ := ctx.Deadline()
+		remaining = time.Until(deadline)
+		return nil
+	}
+
+	r.Start(nil, []Func{record}, WithShutdownTimeout(4*time.Second), WithShutdownBudget(1, 3), WithConcurrentShutdown(2))
+	if remaining < 3*time.Second {
+		t.Fatalf("expected concurrent shutdown functions not to be budgeted, got %v", remaining)
+	}
+}
+
+func TestTimeoutContextCause(t *testing.T) {
+	// Functions whose context is done may be abandoned, so the causes are received rather than shared.
+	causeOf := func(causes chan<- error) Func {
+		return func(ctx context.Context) error {
+			<-ctx.Done()
+			causes <- context.Cause(ctx)
+			return nil
+		}
+	}
+
+	startupCause := make(chan error, 1)
+	New().Start([]Func{causeOf(startupCause)}, nil, WithStartupTimeout(10*time.Millisecond))
+	if err := <-startupCause; !errors.Is(err, ErrStartupTimeout) {
+		t.Fatalf("expected the startup context's cause to be ErrStartupTimeout, got %v", err)
+	}
+
+	// The first function exceeds its own timeout, the second the overall one.
+	funcCause, shutdownCause := make(chan error, 1), make(chan error, 1)
+	r := New()
+	r.Shutdown(nil)
+	r.Start(nil, []Func{causeOf(funcCause), causeOf(shutdownCause)},
+		WithShutdownTimeout(100*time.Millisecond), WithPerShutdownTimeout(60*time.Millisecond))
+	if err := <-funcCause; !errors.Is(err, ErrShutdownFuncTimeout) {
+		t.Fatalf("expected the shutdown function context's cause to be ErrShutdownFuncTimeout, got %v", err)
+	}
+	if err := <-shutdownCause; !errors.Is(err, ErrShutdownTimeout) {
+		t.Fatalf("expected the shutdown context's cause to be ErrShutdownTimeout, got %v", err)
+	}
+}
+
+func TestTimeoutContextCauseWithClock(t *testing.T) {
+	clock := &tickClock{ticks: make(chan time.Time)}
+
+	cause := make(chan error, 1)
+	startup := func(ctx context.Context) error {
+		clock.ticks <- time.Now()
+		<-ctx.Done()
+		cause <- context.Cause(ctx)
+		return ctx.Err()
+	}
+
+	New().Start([]Func{startup}, nil, WithStartupTimeout(time.Hour), WithClock(clock))
+
+	if err := <-cause; !errors.Is(err, ErrStartupTimeout) {
+		t.Fatalf("expected the startup context's cause to be ErrStartupTimeout, got %v", err)
+	}
+}
@@ -0,0 +1,36 @@
+package graceful
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "github.com/bryhen/graceful"
+
+// Starts a span named name as a child of any span in ctx. Returns a nil span if there is no tracer.
+func (c *config) startSpan(ctx context.Context, name string) (context.Context, trace.Span) {
+	if c.tracer == nil {
+		return ctx, nil
+	}
+	return c.tracer.Start(ctx, name)
+}
+
+// Ends span, renaming it to name if one was given with Named() and recording err if non-nil. Safe to call with a nil span.
+func (c *config) endSpan(span trace.Span, name string, err error) {
+	if span == nil {
+		return
+	}
+
+	if name != "" {
+		span.SetName(name)
+	}
+
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	span.End()
+}
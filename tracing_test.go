@@ -0,0 +1,81 @@
+package graceful
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+type recordedSpan struct {
+	noop.Span
+	name   string
+	parent *recordedSpan
+	err    error
+	ended  bool
+}
+
+func (s *recordedSpan) SetName(name string) { s.name = name }
+
+func (s *recordedSpan) RecordError(err error, _ ...trace.EventOption) { s.err = err }
+
+func (s *recordedSpan) End(...trace.SpanEndOption) { s.ended = true }
+
+type recordingTracer struct {
+	noop.Tracer
+	mu    sync.Mutex
+	spans []*recordedSpan
+}
+
+func (rt *recordingTracer) Start(ctx context.Context, name string, _ ...trace.SpanStartOption) (context.Context, trace.Span) {
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+
+	parent, _ := trace.SpanFromContext(ctx).(*recordedSpan)
+	s := &recordedSpan{name: name, parent: parent}
+	rt.spans = append(rt.spans, s)
+	return trace.ContextWithSpan(ctx, s), s
+}
+
+type recordingProvider struct {
+	noop.TracerProvider
+	tracer *recordingTracer
+}
+
+func (rp *recordingProvider) Tracer(string, ...trace.TracerOption) trace.Tracer { return rp.tracer }
+
+func TestWithTracerProvider(t *testing.T) {
+	errBoom := errors.New("boom")
+	tp := &recordingProvider{tracer: &recordingTracer{}}
+
+	ok := func(ctx context.Context) error { return nil }
+	fail := func(ctx context.Context) error { return errBoom }
+
+	r := New()
+	r.Shutdown(nil)
+	r.Start([]Func{Named("db", ok)}, []Func{fail}, WithTracerProvider(tp))
+
+	var names []string
+	for _, s := range tp.tracer.spans {
+		names = append(names, s.name)
+		if !s.ended {
+			t.Errorf("expected span %s to be ended", s.name)
+		}
+	}
+	want := []string{"app.startup", "db", "app.shutdown", "shutdown[0]"}
+	if !slices.Equal(names, want) {
+		t.Fatalf("expected spans %v, got %v", want, names)
+	}
+
+	spans := tp.tracer.spans
+	if spans[1].parent != spans[0] || spans[3].parent != spans[2] {
+		t.Fatal("expected function spans to be children of their phase's span")
+	}
+	if !errors.Is(spans[3].err, errBoom) || !errors.Is(spans[2].err, errBoom) {
+		t.Fatalf("expected the shutdown error to be recorded, got %v and %v", spans[3].err, spans[2].err)
+	}
+}
@@ -0,0 +1,205 @@
+package graceful
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	envListeners = "GRACEFUL_LISTENERS"
+	envReadyFD   = "GRACEFUL_UPGRADE_READY_FD"
+)
+
+// Listeners created with Listen(), which are passed to the child process by Upgrade().
+var (
+	lnMu        sync.Mutex
+	lnInherited map[string][]*os.File
+	lnRegistry  []*upgradeListener
+	lnOnce      sync.Once
+	readyOnce   sync.Once
+)
+
+type upgradeListener struct {
+	key string
+	ln  net.Listener
+}
+
+type filer interface {
+	File() (*os.File, error)
+}
+
+// Same as net.Listen(), but if this process was started by Upgrade(), the listener inherited from the parent for the
+// same network and address is reused instead, so no connections are refused during the upgrade.
+//
+// Only TCP and Unix stream listeners can be passed to a child process.
+func Listen(network, addr string) (net.Listener, error) {
+	lnOnce.Do(parseInherited)
+
+	lnMu.Lock()
+	defer lnMu.Unlock()
+
+	key := network + "|" + addr
+
+	var ln net.Listener
+	if files := lnInherited[key]; len(files) > 0 {
+		lnInherited[key] = files[1:]
+
+		var err error
+		ln, err = net.FileListener(files[0])
+		files[0].Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to inherit listener %s: %w", key, err)
+		}
+	} else {
+		var err error
+		ln, err = net.Listen(network, addr)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	lnRegistry = append(lnRegistry, &upgradeListener{key: key, ln: ln})
+	return ln, nil
+}
+
+// Reads the listeners passed by the parent process, starting at file descriptor 3.
+func parseInherited() {
+	lnInherited = map[string][]*os.File{}
+
+	keys := os.Getenv(envListeners)
+	os.Unsetenv(envListeners)
+	if keys == "" {
+		return
+	}
+
+	for i, key := range strings.Split(keys, ";") {
+		f := os.NewFile(uintptr(3+i), key)
+		lnInherited[key] = append(lnInherited[key], f)
+	}
+}
+
+// Tells the parent process that started this one with Upgrade() that startup has completed. Only the first call has
+// an effect.
+func notifyUpgradeReady() {
+	readyOnce.Do(func() {
+		fd, err := strconv.Atoi(os.Getenv(envReadyFD))
+		os.Unsetenv(envReadyFD)
+		if err != nil {
+			return
+		}
+
+		f := os.NewFile(uintptr(fd), "ready")
+		f.Write([]byte{1})
+		f.Close()
+	})
+}
+
+// Performs a zero-downtime upgrade of the default Runner. See Runner.Upgrade().
+func Upgrade(ctx context.Context) error {
+	return defaultRunner.Upgrade(ctx)
+}
+
+// Re-executes the current binary, passing it the listeners created with Listen(). Once the child completes startup,
+// shutdown of this Runner is triggered so it drains and exits while the child keeps serving.
+//
+// If the child exits or ctx is done before the child is ready, the child is killed and an error is returned. This
+// process keeps running in that case.
+//
+// Usually triggered by a signal during the run phase:
+//
+//	graceful.WithReloadSignal(syscall.SIGUSR2, graceful.Upgrade)
+func (r *Runner) Upgrade(ctx context.Context) error {
+	exe, err := os.Executable()
+	if err != nil {
+		return err
+	}
+
+	lnMu.Lock()
+	var files []*os.File
+	var keys []string
+	for _, ul := range lnRegistry {
+		f, ok := ul.ln.(filer)
+		if !ok {
+			continue
+		}
+
+		file, err := f.File()
+		if err != nil {
+			lnMu.Unlock()
+			closeFiles(files)
+			return fmt.Errorf("failed to get file of listener %s: %w", ul.key, err)
+		}
+
+		files = append(files, file)
+		keys = append(keys, ul.key)
+	}
+	lnMu.Unlock()
+	defer closeFiles(files)
+
+	readyR, readyW, err := os.Pipe()
+	if err != nil {
+		return err
+	}
+	defer readyR.Close()
+
+	cmd := exec.Command(exe, os.Args[1:]...)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	cmd.ExtraFiles = append(files, readyW)
+	cmd.Env = append(os.Environ(),
+		envListeners+"="+strings.Join(keys, ";"),
+		envReadyFD+"="+strconv.Itoa(3+len(files)),
+	)
+
+	wait, err := startChild(cmd)
+	readyW.Close()
+	if err != nil {
+		return err
+	}
+
+	ready := make(chan error, 1)
+	go func() {
+		b := make([]byte, 1)
+		_, err := readyR.Read(b)
+		ready <- err
+	}()
+
+	select {
+	case err := <-ready:
+		if err != nil {
+			cmd.Process.Kill()
+			wait()
+			return errors.New("upgraded process exited before it was ready")
+		}
+	case <-ctx.Done():
+		cmd.Process.Kill()
+		wait()
+		return ctx.Err()
+	}
+
+	// The socket files must outlive this process now, since the child keeps using them. Until then, closing a Unix
+	// listener removes its socket file as usual, so a plain restart can bind the same path.
+	lnMu.Lock()
+	for _, ul := range lnRegistry {
+		if ln, ok := ul.ln.(*net.UnixListener); ok {
+			keepSocketFile(ln)
+		}
+	}
+	lnMu.Unlock()
+
+	cmd.Process.Release()
+	r.Shutdown(nil)
+	return nil
+}
+
+func closeFiles(files []*os.File) {
+	for _, f := range files {
+		f.Close()
+	}
+}
@@ -0,0 +1,10 @@
+//go:build !plan9
+
+package graceful
+
+import "net"
+
+// Stops ln from removing its socket file when closed.
+func keepSocketFile(ln *net.UnixListener) {
+	ln.SetUnlinkOnClose(false)
+}
@@ -0,0 +1,6 @@
+package graceful
+
+import "net"
+
+// Plan 9 has no Unix domain sockets, so there is no socket file to keep.
+func keepSocketFile(ln *net.UnixListener) {}
@@ -0,0 +1,69 @@
+package graceful
+
+import (
+	"errors"
+	"io/fs"
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestListenUnixRemovesSocketOnClose(t *testing.T) {
+	if runtime.GOOS == "windows" || runtime.GOOS == "plan9" {
+		t.Skip("unix sockets are not supported")
+	}
+
+	path := filepath.Join(t.TempDir(), "sock")
+
+	// Without an upgrade, closing the listener must remove the socket file so a plain restart can bind it again.
+	for i := range 2 {
+		ln, err := Listen("unix", path)
+		if err != nil {
+			t.Fatalf("listen %d: %v", i, err)
+		}
+		if err := ln.Close(); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := os.Stat(path); !errors.Is(err, fs.ErrNotExist) {
+			t.Fatalf("expected the socket file to be removed, got %v", err)
+		}
+	}
+}
+
+func TestListenInheritsListener(t *testing.T) {
+	if runtime.GOOS == "windows" || runtime.GOOS == "plan9" {
+		t.Skip("listeners cannot be inherited")
+	}
+
+	// Stands in for the listener passed by the parent process.
+	parent, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := parent.Addr().String()
+	f, err := parent.(*net.TCPListener).File()
+	parent.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lnOnce.Do(parseInherited)
+	lnMu.Lock()
+	lnInherited["tcp|"+addr] = append(lnInherited["tcp|"+addr], f)
+	lnMu.Unlock()
+
+	// Binding addr anew would fail, since the inherited socket still holds it.
+	ln, err := Listen("tcp", addr)
+	if err != nil {
+		t.Fatalf("expected the listener to be inherited, got %v", err)
+	}
+	defer ln.Close()
+
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn.Close()
+}
@@ -0,0 +1,39 @@
+//go:build unix
+
+package graceful
+
+import (
+	"os"
+	"strconv"
+	"sync"
+	"syscall"
+	"testing"
+)
+
+func TestNotifyUpgradeReady(t *testing.T) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	// The descriptor is closed by notifyUpgradeReady(), so it must not be owned by w as well.
+	fd, err := syscall.Dup(int(w.Fd()))
+	w.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Every lifecycle that completes startup calls notifyUpgradeReady(), so earlier tests have used up readyOnce.
+	readyOnce = sync.Once{}
+	t.Setenv(envReadyFD, strconv.Itoa(fd))
+	notifyUpgradeReady()
+
+	b := make([]byte, 1)
+	if _, err := r.Read(b); err != nil || b[0] != 1 {
+		t.Fatalf("expected the parent to be notified, got %v", err)
+	}
+	if _, ok := os.LookupEnv(envReadyFD); ok {
+		t.Fatal("expected the variable to be unset so it is not passed on")
+	}
+}
@@ -0,0 +1,46 @@
+package graceful
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Checks the wiring of an application without running it, ie as a fast CI check: the options are parsed, and nil
+// functions, rollback functions without a matching startup function, and options which depend on others are reported.
+// Returns nil if no problems were found, otherwise every problem combined with errors.Join(). Invalid options match
+// ErrInvalidOption.
+//
+// Functions are opaque, so components declared with a Graph are not checked. Use Graph.Validate() for those.
+func Validate(startupFns []Func, shutdownFns []Func, opts ...Option) error {
+	var errs []error
+
+	config := &config{signals: defaultSignals()}
+	if err := parseOptions(config, opts); err != nil {
+		errs = append(errs, err)
+	}
+
+	errs = append(errs, nilFuncs("startup", startupFns)...)
+	errs = append(errs, nilFuncs("shutdown", shutdownFns)...)
+	errs = append(errs, nilFuncs("run", config.runFns)...)
+	for sig, fns := range config.reloadFns {
+		errs = append(errs, nilFuncs("reload "+sig.String(), fns)...)
+	}
+
+	// Nil rollback functions are allowed, they mark steps which need no rollback.
+	if len(config.rollbackFns) > len(startupFns) {
+		errs = append(errs, fmt.Errorf("%d rollback functions for %d startup functions", len(config.rollbackFns),
+			len(startupFns)))
+	}
+
+	return errors.Join(errs...)
+}
+
+func nilFuncs(phase string, fns []Func) []error {
+	var errs []error
+	for i, fn := range fns {
+		if fn == nil {
+			errs = append(errs, fmt.Errorf("%s[%d] is nil", phase, i))
+		}
+	}
+	return errs
+}
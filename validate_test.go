@@ -0,0 +1,29 @@
+package graceful
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestValidate(t *testing.T) {
+	ok := func(ctx context.Context) error { return nil }
+
+	if err := Validate([]Func{ok}, []Func{ok}, WithRun(ok), WithStartupRollback([]Func{nil})); err != nil {
+		t.Fatalf("expected valid wiring, got %v", err)
+	}
+
+	err := Validate([]Func{ok, nil}, []Func{nil}, WithStartupRollback([]Func{ok, ok, ok}),
+		WithBackgroundShutdown(time.Second))
+
+	for _, want := range []string{"startup[1] is nil", "shutdown[0] is nil", "3 rollback functions for 2 startup functions"} {
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Fatalf("expected %q to be reported, got %v", want, err)
+		}
+	}
+	if !errors.Is(err, ErrInvalidOption) {
+		t.Fatalf("expected the invalid option to match ErrInvalidOption, got %v", err)
+	}
+}
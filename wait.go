@@ -0,0 +1,124 @@
+package graceful
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+)
+
+// Configures WaitForTCP() and WaitForHTTP().
+type WaitOption func(*waitConfig)
+
+type waitConfig struct {
+	initial        time.Duration
+	max            time.Duration
+	attemptTimeout time.Duration
+}
+
+// The first retry waits initial and each subsequent one waits twice as long as the previous, up to max.
+// Default: 100ms doubling up to 5s.
+func WithWaitBackoff(initial time.Duration, max time.Duration) WaitOption {
+	return func(wc *waitConfig) {
+		wc.initial = initial
+		wc.max = max
+	}
+}
+
+// Maximum amount of time each attempt may take. Default: 5s.
+func WithWaitAttemptTimeout(d time.Duration) WaitOption {
+	return func(wc *waitConfig) {
+		wc.attemptTimeout = d
+	}
+}
+
+func newWaitConfig(opts []WaitOption) *waitConfig {
+	wc := &waitConfig{
+		initial:        100 * time.Millisecond,
+		max:            5 * time.Second,
+		attemptTimeout: 5 * time.Second,
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(wc)
+		}
+	}
+	return wc
+}
+
+// Returns a startup function which blocks until a TCP connection to addr (ie "postgres:5432") succeeds, retrying with
+// backoff. Fails once the context is done, reporting the last connection error.
+func WaitForTCP(addr string, opts ...WaitOption) Func {
+	wc := newWaitConfig(opts)
+	return func(ctx context.Context) error {
+		return wc.poll(ctx, "tcp "+addr, func(ctx context.Context) error {
+			var d net.Dialer
+			conn, err := d.DialContext(ctx, "tcp", addr)
+			if err != nil {
+				return err
+			}
+			return conn.Close()
+		})
+	}
+}
+
+// Returns a startup function which blocks until a GET request to url responds with expectStatus, retrying with backoff.
+// An expectStatus of 0 accepts any 2xx status. Fails once the context is done, reporting the last error.
+func WaitForHTTP(url string, expectStatus int, opts ...WaitOption) Func {
+	wc := newWaitConfig(opts)
+	return func(ctx context.Context) error {
+		return wc.poll(ctx, "http "+url, func(ctx context.Context) error {
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+			if err != nil {
+				return err
+			}
+
+			res, err := http.DefaultClient.Do(req)
+			if err != nil {
+				return err
+			}
+			res.Body.Close()
+
+			if (expectStatus == 0 && res.StatusCode/100 == 2) || res.StatusCode == expectStatus {
+				return nil
+			}
+			return fmt.Errorf("unexpected status %d", res.StatusCode)
+		})
+	}
+}
+
+// Returns a startup function which calls probe every interval until it returns nil. Fails once the context is done,
+// reporting probe's last error.
+func WaitForFunc(probe Func, interval time.Duration) Func {
+	wc := &waitConfig{initial: interval, max: interval}
+	return func(ctx context.Context) error {
+		return wc.poll(ctx, "probe", probe)
+	}
+}
+
+func (wc *waitConfig) poll(ctx context.Context, target string, probe Func) error {
+	backoff := max(wc.initial, time.Millisecond)
+	for {
+		attemptCtx, cancel := ctx, context.CancelFunc(nop)
+		if wc.attemptTimeout > 0 {
+			attemptCtx, cancel = context.WithTimeout(ctx, wc.attemptTimeout)
+		}
+		err := probe(attemptCtx)
+		cancel()
+
+		if err == nil {
+			return nil
+		}
+
+		t := time.NewTimer(backoff)
+		select {
+		case <-t.C:
+		case <-ctx.Done():
+			t.Stop()
+			return fmt.Errorf("waiting for %s: %w: last error: %w", target, ctx.Err(), err)
+		}
+
+		backoff = max(min(backoff*2, wc.max), time.Millisecond)
+	}
+}
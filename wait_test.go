@@ -0,0 +1,77 @@
+package graceful
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWaitForTCP(t *testing.T) {
+	ln := listenLocal(t)
+	addr := ln.Addr().String()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := WaitForTCP(addr)(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	// Nothing listens once the listener is closed, so waiting fails when the context is done.
+	ln.Close()
+	ctx, cancel = context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := WaitForTCP(addr, WithWaitBackoff(time.Millisecond, 10*time.Millisecond))(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected the context error, got %v", err)
+	}
+}
+
+func TestWaitForHTTP(t *testing.T) {
+	ln := listenLocal(t)
+
+	var requests atomic.Int32
+	srv := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Unavailable until the third request.
+		if requests.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	})}
+	go srv.Serve(ln)
+	defer srv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := WaitForHTTP("http://"+ln.Addr().String(), 0, WithWaitBackoff(time.Millisecond, time.Millisecond))(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if n := requests.Load(); n != 3 {
+		t.Fatalf("expected 3 requests, got %d", n)
+	}
+}
+
+func TestWaitForFunc(t *testing.T) {
+	errNotYet := errors.New("not yet")
+
+	calls := 0
+	probe := func(ctx context.Context) error {
+		if calls++; calls < 3 {
+			return errNotYet
+		}
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := WaitForFunc(probe, time.Millisecond)(ctx); err != nil || calls != 3 {
+		t.Fatalf("expected success on the third call, got %v after %d calls", err, calls)
+	}
+
+	ctx, cancel = context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	never := func(ctx context.Context) error { return errNotYet }
+	if err := WaitForFunc(never, time.Millisecond)(ctx); !errors.Is(err, errNotYet) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected the last probe error and the context error, got %v", err)
+	}
+}
@@ -0,0 +1,83 @@
+package graceful
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"slices"
+	"time"
+)
+
+// Loads a configuration file and reloads it when it changes. See WatchConfig().
+type ConfigWatcher struct {
+	path  string
+	parse func([]byte) error
+}
+
+// Returns a ConfigWatcher which passes the contents of path to parse whenever it is loaded:
+//
+//	cw := graceful.WatchConfig("config.yaml", applyConfig)
+//	graceful.Start([]graceful.Func{cw.Load}, shutdownFns,
+//		graceful.WithReloadSignal(syscall.SIGHUP, cw.Load),
+//		graceful.WithRun(cw.Watch(graceful.Default(), syscall.SIGHUP, 5*time.Second)))
+//
+// The file is loaded by the startup function, then again whenever the reload signal is received or the file changes,
+// until shutdown begins. Errors while running are reported like those of any reload function rather than triggering
+// shutdown, so parse should leave the previous configuration in place when it fails.
+func WatchConfig(path string, parse func([]byte) error) *ConfigWatcher {
+	return &ConfigWatcher{path: path, parse: parse}
+}
+
+// Reads the file and passes it to parse. Use it as a startup function and as a reload function.
+func (cw *ConfigWatcher) Load(ctx context.Context) error {
+	b, err := os.ReadFile(cw.path)
+	if err != nil {
+		return fmt.Errorf("failed to read config: %w", err)
+	}
+
+	if err := cw.parse(b); err != nil {
+		return fmt.Errorf("failed to parse config %s: %w", cw.path, err)
+	}
+	return nil
+}
+
+// Returns a run function (see WithRun()) which checks the file every interval and delivers sig to r (see
+// Runner.Signal()) when it has changed, so the reload functions registered for sig run as if it had been received from
+// the OS. sig must be registered with WithReloadSignal() with Load() among its functions. The watcher stops when
+// shutdown begins. Use graceful.Default() for the package-level functions. interval is measured by the Clock given to
+// WithClock(), if any.
+func (cw *ConfigWatcher) Watch(r *Runner, sig os.Signal, interval time.Duration) Func {
+	return watchFiles(r, sig, interval, cw.path)
+}
+
+// Returns a run function which polls the modification times of paths every interval (measured by the Clock given to
+// WithClock(), if any) and delivers sig to r when any has changed. Missing files have a zero time, so they are picked up
+// once they appear.
+func watchFiles(r *Runner, sig os.Signal, interval time.Duration, paths ...string) Func {
+	modTimes := func() []time.Time {
+		mt := make([]time.Time, len(paths))
+		for i, name := range paths {
+			if fi, err := os.Stat(name); err == nil {
+				mt[i] = fi.ModTime()
+			}
+		}
+		return mt
+	}
+
+	return func(ctx context.Context) error {
+		last := modTimes()
+
+		for {
+			select {
+			case <-afterFrom(ctx, interval):
+			case <-ctx.Done():
+				return nil
+			}
+
+			if mt := modTimes(); !slices.EqualFunc(mt, last, time.Time.Equal) {
+				last = mt
+				r.Signal(sig)
+			}
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package graceful
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestConfigWatcherLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config")
+	errParse := errors.New("parse")
+	var loaded string
+	cw := WatchConfig(path, func(b []byte) error {
+		if string(b) == "bad" {
+			return errParse
+		}
+		loaded = string(b)
+		return nil
+	})
+
+	if err := cw.Load(context.Background()); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("expected a missing file to fail with os.ErrNotExist, got %v", err)
+	}
+
+	if err := os.WriteFile(path, []byte("good"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := cw.Load(context.Background()); err != nil || loaded != "good" {
+		t.Fatalf("expected the config to be loaded, got %q, %v", loaded, err)
+	}
+
+	if err := os.WriteFile(path, []byte("bad"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := cw.Load(context.Background()); !errors.Is(err, errParse) || loaded != "good" {
+		t.Fatalf("expected the parse error with the previous config kept, got %q, %v", loaded, err)
+	}
+}
+
+func TestConfigWatcherWatch(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config")
+	cw := WatchConfig(path, func([]byte) error { return nil })
+
+	r := New()
+	reload := testSignal("reload")
+	sigs := make(chan os.Signal, 1)
+	r.notify(sigs, reload)
+	defer r.stopNotify(sigs)
+
+	clock := &tickClock{ticks: make(chan time.Time)}
+	ctx, cancel := context.WithCancel(context.WithValue(context.Background(), clockKey{}, Clock(clock)))
+	done := make(chan error)
+	go func() { done <- cw.Watch(r, reload, time.Minute)(ctx) }()
+
+	// The first tick is received once the watcher has recorded the initial state. A missing file is picked up once it
+	// appears.
+	clock.ticks <- time.Now()
+	if err := os.WriteFile(path, []byte("config"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	clock.ticks <- time.Now()
+	select {
+	case <-sigs:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the new file to trigger a reload")
+	}
+
+	later := time.Now().Add(time.Hour)
+	if err := os.Chtimes(path, later, later); err != nil {
+		t.Fatal(err)
+	}
+	clock.ticks <- time.Now()
+	select {
+	case <-sigs:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the change to trigger a reload")
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatalf("expected nil once ctx is done, got %v", err)
+	}
+}
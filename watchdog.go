@@ -0,0 +1,122 @@
+package graceful
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync/atomic"
+	"time"
+)
+
+// Reported in ExitReason.ErrRuntime (wrapped, naming the stalled component) when the watchdog configured with
+// WithWatchdog() was not pinged in time.
+var ErrWatchdog = errors.New("watchdog timeout")
+
+// Pings the default Runner's watchdog. See WithWatchdog().
+func Ping() {
+	defaultRunner.Ping()
+}
+
+// Same as the package-level Ping(), but scoped to this Runner.
+func (r *Runner) Ping() {
+	r.pinger("").Add(1)
+}
+
+// Returns a function which pings the default Runner's watchdog on behalf of the named component. Once a component has
+// a pinger, it must keep pinging on its own, so a single stalled component triggers shutdown even while others are
+// healthy. See WithWatchdog().
+func Pinger(name string) (ping func()) {
+	return defaultRunner.Pinger(name)
+}
+
+// Same as the package-level Pinger(), but scoped to this Runner.
+func (r *Runner) Pinger(name string) (ping func()) {
+	counter := r.pinger(name)
+	return func() {
+		counter.Add(1)
+	}
+}
+
+func (r *Runner) pinger(name string) *atomic.Uint64 {
+	r.pingMu.Lock()
+	defer r.pingMu.Unlock()
+
+	if r.pings == nil {
+		r.pings = map[string]*atomic.Uint64{}
+	}
+	if r.pings[name] == nil {
+		r.pings[name] = &atomic.Uint64{}
+	}
+	return r.pings[name]
+}
+
+// Watches for Ping() and Pinger() calls during the run phase. Every interval, the pingers are checked and if one has
+// not pinged for longer than timeout, shutdown is triggered with ErrWatchdog. Each pinger is watched from the run phase
+// beginning or its first use, whichever is later, so Ping() is only watched once it has been called. Default: no
+// watchdog.
+func WithWatchdog(interval, timeout time.Duration) Option {
+	return func(config *config) error {
+		if interval <= 0 || timeout <= 0 {
+			return invalidOption("watchdog interval and timeout must be positive")
+		}
+		config.watchdogInterval = interval
+		config.watchdogTimeout = timeout
+		return nil
+	}
+}
+
+type pingState struct {
+	count uint64
+	at    time.Time
+}
+
+// Launches the watchdog, which returns once ctx is done.
+func (r *Runner) watchdog(ctx context.Context, config *config) {
+	if config.watchdogInterval == 0 {
+		return
+	}
+
+	seen := map[string]pingState{}
+	check := func() error {
+		r.pingMu.Lock()
+		defer r.pingMu.Unlock()
+
+		now := config.now()
+		for name, counter := range r.pings {
+			count := counter.Load()
+			st, ok := seen[name]
+			if !ok || count != st.count {
+				seen[name] = pingState{count: count, at: now}
+				continue
+			}
+
+			if stalled := now.Sub(st.at); stalled > config.watchdogTimeout {
+				if name == "" {
+					return fmt.Errorf("%w: not pinged for %s", ErrWatchdog, stalled)
+				}
+				return fmt.Errorf("%w: %q not pinged for %s", ErrWatchdog, name, stalled)
+			}
+		}
+		return nil
+	}
+
+	// Pings before the run phase began are not counted.
+	check()
+
+	go func() {
+		for {
+			select {
+			case <-config.after(config.watchdogInterval):
+			case <-ctx.Done():
+				return
+			}
+
+			if err := check(); err != nil {
+				config.log(slog.LevelError, "watchdog timeout, shutting down", slog.Any("err", err))
+				r.ShutdownWithCause(err, "watchdog")
+				return
+			}
+		}
+	}()
+}
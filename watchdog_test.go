@@ -0,0 +1,69 @@
+package graceful
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWatchdog(t *testing.T) {
+	r := New()
+	ping := r.Pinger("worker")
+
+	run := func(ctx context.Context) error {
+		ping()
+		<-ctx.Done()
+		return nil
+	}
+
+	er := r.Start(nil, nil, WithRun(run), WithWatchdog(5*time.Millisecond, 20*time.Millisecond))
+
+	if !errors.Is(er.ErrRuntime, ErrWatchdog) || !strings.Contains(er.ErrRuntime.Error(), `"worker"`) ||
+		er.Reason != "watchdog" {
+		t.Fatalf("expected a watchdog timeout naming the worker, got %v (%q)", er.ErrRuntime, er.Reason)
+	}
+}
+
+func TestWatchdogPinged(t *testing.T) {
+	r := New()
+
+	run := func(ctx context.Context) error {
+		deadline := time.After(100 * time.Millisecond)
+		for {
+			select {
+			case <-time.After(time.Millisecond):
+				r.Ping()
+			case <-deadline:
+				r.Stop()
+				<-ctx.Done()
+				return nil
+			case <-ctx.Done():
+				return nil
+			}
+		}
+	}
+
+	er := r.Start(nil, nil, WithRun(run), WithWatchdog(5*time.Millisecond, 20*time.Millisecond))
+
+	if er.ErrRuntime != nil {
+		t.Fatalf("expected no watchdog timeout while pinging, got %v", er.ErrRuntime)
+	}
+}
+
+func TestWatchdogUnusedPingerIgnored(t *testing.T) {
+	r := New()
+
+	run := func(ctx context.Context) error {
+		time.Sleep(50 * time.Millisecond)
+		r.Stop()
+		return nil
+	}
+
+	er := r.Start(nil, nil, WithRun(run), WithWatchdog(5*time.Millisecond, 20*time.Millisecond))
+
+	if er.ErrRuntime != nil {
+		t.Fatalf("expected no watchdog timeout without pingers, got %v", er.ErrRuntime)
+	}
+}